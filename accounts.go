@@ -0,0 +1,338 @@
+package fplib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// Account is a single balance, such as a checking account, a savings account
+// or a credit card, that transactions can be attributed to.
+type Account struct {
+	ID           string `yaml:"id"`
+	Name         string `yaml:"name"`
+	StartBalance int    `yaml:"startBalance"` // in cents; 500 = $5.00
+	// Type is empty for a regular account, or CreditCard. The balance of a
+	// credit card is negative when money is owed on it.
+	Type string `yaml:"type"`
+
+	// The remaining fields only apply to CreditCard accounts. Charges to the
+	// card accumulate until the statement closes, and the statement balance
+	// is paid from FundingAccount PaymentDueDays days later.
+
+	// The day of the month that the statement closes on. Months that are
+	// shorter close on their last day instead.
+	StatementClosingDay int `yaml:"statementClosingDay"`
+	// The number of days after the statement closes that the payment is made.
+	PaymentDueDays int `yaml:"paymentDueDays"`
+	// Either PayInFull (the default, which an empty value also means) or
+	// MinimumPayment.
+	PaymentPolicy string `yaml:"paymentPolicy"`
+	// With the MinimumPayment policy, the payment is the larger of this
+	// amount and MinimumPaymentPercent of the statement balance, but never
+	// more than the statement balance. Interest is not modeled.
+	MinimumPayment        int `yaml:"minimumPayment"`
+	MinimumPaymentPercent int `yaml:"minimumPaymentPercent"`
+	// The ID of the account that pays the statement. An empty value refers to
+	// the first account passed to GetAccountResults.
+	FundingAccount string `yaml:"fundingAccount"`
+}
+
+// AccountResults holds the output of GetAccountResults.
+type AccountResults struct {
+	// Accounts contains the results of each account, keyed by Account.ID.
+	Accounts map[string][]Result
+	// Combined contains the results of every account added together.
+	// Transfers between accounts cancel out, so they are not included.
+	Combined []Result
+}
+
+// GetAccountResults is like GetResults, but it tracks a separate balance for
+// each of the provided accounts, starting from each account's own
+// StartBalance. Each transaction is attributed to the account referenced by
+// its Account field, and transfers (see TX.TransferTo) debit one account and
+// credit another. Statement payments for CreditCard accounts are added as
+// transfers from their funding accounts on each payment due date, which
+// requires startDate to be at midnight, since that's when statements close.
+func GetAccountResults(tx []TX, accounts []Account, startDate time.Time, endDate time.Time, statusHook func(status string)) (AccountResults, error) {
+	return GetAccountResultsWithOptions(context.Background(), tx, accounts, startDate, endDate, ResultsOptions{
+		Progress: StatusHookReporter(statusHook),
+	})
+}
+
+// GetAccountResultsWithOptions is the same as GetAccountResults, except that
+// progress reporting is controlled by opts, and if ctx is cancelled or its
+// deadline passes before every account has been projected, ctx.Err() is
+// returned. opts.StartBalance is ignored, since every account has its own.
+// PhaseAccounts is reported before each projection: one for the statements of
+// each credit card, one for each account, and one for the combined results.
+func GetAccountResultsWithOptions(ctx context.Context, tx []TX, accounts []Account, startDate time.Time, endDate time.Time, opts ResultsOptions) (AccountResults, error) {
+	if len(accounts) == 0 {
+		return AccountResults{}, errors.New("at least one account is required")
+	}
+
+	accountTXs := make(map[string][]TX)
+	combinedTXs := []TX{}
+	combinedBalance := 0
+
+	for _, a := range accounts {
+		if _, ok := accountTXs[a.ID]; ok {
+			return AccountResults{}, fmt.Errorf("duplicate account id: %v", a.ID)
+		}
+
+		if a.Type != "" && a.Type != CreditCard {
+			return AccountResults{}, fmt.Errorf("unknown type for account %v: %v", a.Name, a.Type)
+		}
+
+		accountTXs[a.ID] = []TX{}
+		combinedBalance += a.StartBalance
+	}
+
+	resolve := func(id string) (string, error) {
+		if id == "" {
+			return accounts[0].ID, nil
+		}
+
+		if _, ok := accountTXs[id]; !ok {
+			return "", fmt.Errorf("unknown account id: %v", id)
+		}
+
+		return id, nil
+	}
+
+	for _, txi := range tx {
+		from, err := resolve(txi.Account)
+		if err != nil {
+			return AccountResults{}, fmt.Errorf("failed to process tx %v: %v", txi.Name, err.Error())
+		}
+
+		// resolve the account so that every result entry refers to it
+		txi.Account = from
+
+		if txi.TransferTo == "" {
+			accountTXs[from] = append(accountTXs[from], txi)
+			combinedTXs = append(combinedTXs, txi)
+
+			continue
+		}
+
+		to, err := resolve(txi.TransferTo)
+		if err != nil {
+			return AccountResults{}, fmt.Errorf("failed to process tx %v: %v", txi.Name, err.Error())
+		}
+
+		if from == to {
+			return AccountResults{}, fmt.Errorf("tx %v transfers to its own account %v", txi.Name, from)
+		}
+
+		credit := withSign(txi, 1)
+		credit.Account = to
+
+		accountTXs[from] = append(accountTXs[from], withSign(txi, -1))
+		accountTXs[to] = append(accountTXs[to], credit)
+	}
+
+	projections := len(accounts) + 1
+
+	for _, a := range accounts {
+		if a.Type == CreditCard {
+			projections++
+		}
+	}
+
+	progress := newProgressTracker(opts.Progress, opts.ProgressInterval)
+	projected := 0
+
+	// project reports the start of the next projection and returns its
+	// results, which are described by what in any error other than ctx's
+	project := func(tx []TX, startBalance int, what string) ([]Result, error) {
+		progress.report(PhaseAccounts, projected, projections)
+		projected++
+
+		r, err := getResults(ctx, tx, startDate, endDate, startBalance, progress)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+
+			return nil, fmt.Errorf("failed to get %v: %v", what, err.Error())
+		}
+
+		return r, nil
+	}
+
+	for _, a := range accounts {
+		if a.Type != CreditCard {
+			continue
+		}
+
+		funding, err := resolve(a.FundingAccount)
+		if err != nil {
+			return AccountResults{}, fmt.Errorf("failed to process account %v: %v", a.Name, err.Error())
+		}
+
+		for _, f := range accounts {
+			if f.ID == funding && f.Type == CreditCard {
+				return AccountResults{}, fmt.Errorf("account %v cannot be funded by credit card %v", a.Name, f.Name)
+			}
+		}
+
+		if a.StatementClosingDay < 1 || a.StatementClosingDay > DaysInMonth {
+			return AccountResults{}, fmt.Errorf("invalid statement closing day for account %v: %v", a.Name, a.StatementClosingDay)
+		}
+
+		if a.PaymentPolicy != "" && a.PaymentPolicy != PayInFull && a.PaymentPolicy != MinimumPayment {
+			return AccountResults{}, fmt.Errorf("unknown payment policy for account %v: %v", a.Name, a.PaymentPolicy)
+		}
+
+		// statements close at midnight, which is only one of the results' days
+		// if the results start at midnight too
+		if h, m, sec := startDate.Clock(); h != 0 || m != 0 || sec != 0 {
+			return AccountResults{}, fmt.Errorf("statements for account %v require a start date at midnight: %v", a.Name, startDate)
+		}
+
+		charges, err := project(accountTXs[a.ID], a.StartBalance, fmt.Sprintf("results for account %v", a.Name))
+		if err != nil {
+			return AccountResults{}, err
+		}
+
+		payments := getStatementPayments(a, charges, startDate, endDate)
+
+		for _, p := range payments {
+			debit := withSign(p, -1)
+			debit.Account = funding
+
+			accountTXs[a.ID] = append(accountTXs[a.ID], p)
+			accountTXs[funding] = append(accountTXs[funding], debit)
+		}
+	}
+
+	results := AccountResults{Accounts: make(map[string][]Result)}
+
+	for _, a := range accounts {
+		r, err := project(accountTXs[a.ID], a.StartBalance, fmt.Sprintf("results for account %v", a.Name))
+		if err != nil {
+			return AccountResults{}, err
+		}
+
+		results.Accounts[a.ID] = r
+	}
+
+	r, err := project(combinedTXs, combinedBalance, "combined results")
+	if err != nil {
+		return AccountResults{}, err
+	}
+
+	results.Combined = r
+
+	progress.report(PhaseDone, projections, projections)
+
+	return results, nil
+}
+
+// withSign returns a copy of the transaction whose amounts (including any
+// scheduled amounts) are all positive if sign is positive, or all negative
+// otherwise.
+func withSign(txi TX, sign int) TX {
+	if sign < 0 {
+		sign = -1
+	} else {
+		sign = 1
+	}
+
+	txi.Amount = sign * abs(txi.Amount)
+
+	schedule := make([]ScheduledAmount, len(txi.AmountSchedule))
+	for i, sa := range txi.AmountSchedule {
+		schedule[i] = ScheduledAmount{Effective: sa.Effective, Amount: sign * abs(sa.Amount)}
+	}
+
+	txi.AmountSchedule = schedule
+
+	return txi
+}
+
+// getStatementPayments walks through every statement of a credit card account
+// that closes between startDate and endDate and returns a one-time
+// transaction, credited to the card, for each resulting payment that is due
+// within the window. charges are the card's results before any payments.
+func getStatementPayments(card Account, charges []Result, startDate time.Time, endDate time.Time) []TX {
+	dayIndex := make(map[int64]int)
+	for i, r := range charges {
+		dayIndex[r.Date.Unix()] = i
+	}
+
+	payments := []TX{}
+	// the payments that have been made so far, by their due date
+	paid := make(map[int64]int)
+
+	for y, m := startDate.Year(), startDate.Month(); ; m++ {
+		// the zeroth day of the next month is the last day of this month
+		lastDay := time.Date(y, m+1, 0, 0, 0, 0, 0, startDate.Location()).Day()
+		closes := time.Date(y, m, min(card.StatementClosingDay, lastDay), 0, 0, 0, 0, startDate.Location())
+
+		if closes.After(endDate) {
+			break
+		}
+
+		i, ok := dayIndex[closes.Unix()]
+		if !ok {
+			continue
+		}
+
+		balance := charges[i].Balance
+		for due, amount := range paid {
+			if due <= closes.Unix() {
+				balance += amount
+			}
+		}
+
+		owed := -balance
+		if owed <= 0 {
+			continue
+		}
+
+		payment := owed
+		if card.PaymentPolicy == MinimumPayment {
+			payment = min(max(card.MinimumPayment, owed*card.MinimumPaymentPercent/100), owed)
+		}
+
+		due := closes.AddDate(0, 0, card.PaymentDueDays)
+		if payment <= 0 || due.After(endDate) {
+			continue
+		}
+
+		paid[due.Unix()] += payment
+
+		// each statement's payment has its own ID, such as
+		// "card-payment-2025-01-25" for the statement that closed on January
+		// 25th, 2025
+		p := newOneTimeTX(fmt.Sprintf("%v payment", card.Name), payment, due)
+		p.ID = fmt.Sprintf("%v-payment-%v", card.ID, GetNowDateString(closes))
+		p.Account = card.ID
+
+		payments = append(payments, p)
+	}
+
+	return payments
+}
+
+// newOneTimeTX returns an active transaction that occurs only on dt.
+func newOneTimeTX(name string, amount int, dt time.Time) TX {
+	return TX{
+		Amount:      amount,
+		Active:      true,
+		Name:        name,
+		Frequency:   rrule.DAILY.String(),
+		Interval:    1,
+		StartsDay:   dt.Day(),
+		StartsMonth: int(dt.Month()),
+		StartsYear:  dt.Year(),
+		EndsDay:     dt.Day(),
+		EndsMonth:   int(dt.Month()),
+		EndsYear:    dt.Year(),
+	}
+}
@@ -0,0 +1,343 @@
+package fplib_test
+
+import (
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestGetAccountResults(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	const (
+		checking = "checking"
+		savings  = "savings"
+	)
+
+	accounts := []fpl.Account{
+		{ID: checking, Name: "Checking", StartBalance: 100000},
+		{ID: savings, Name: "Savings", StartBalance: 500000},
+	}
+
+	monthly := func(name string, amount int, account, transferTo string) fpl.TX {
+		tx := monthlyTX("", name, amount, 1)
+		tx.Account = account
+		tx.TransferTo = transferTo
+
+		return tx
+	}
+
+	tests := []struct {
+		tx []fpl.TX
+		// final balances for each account, and combined
+		wantChecking, wantSavings, wantCombined int
+		err                                     bool
+	}{
+		{
+			[]fpl.TX{
+				// an empty account refers to the first account
+				monthly("Paycheck", 300000, "", ""),
+				monthly("Rent", -150000, checking, ""),
+				// the sign of a transfer's amount doesn't matter
+				monthly("Save", -50000, checking, savings),
+				monthly("Interest", 1000, savings, ""),
+			},
+			100000 + 12*(300000-150000-50000),
+			500000 + 12*(50000+1000),
+			600000 + 12*(300000-150000+1000),
+			false,
+		},
+		{
+			[]fpl.TX{monthly("Rent", -150000, "nonexistent", "")},
+			0, 0, 0,
+			true,
+		},
+		{
+			[]fpl.TX{monthly("Save", -50000, checking, "nonexistent")},
+			0, 0, 0,
+			true,
+		},
+		{
+			[]fpl.TX{monthly("Save", -50000, checking, checking)},
+			0, 0, 0,
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		got, err := fpl.GetAccountResults(test.tx, accounts, start, end, nil)
+		if err != nil && !test.err {
+			t.Logf("test %v threw error when it wasn't supposed to: %v", i, err.Error())
+			t.FailNow()
+		} else if err != nil && test.err {
+			continue
+		} else if test.err {
+			t.Logf("test %v did not throw an error when it was supposed to", i)
+			t.FailNow()
+		}
+
+		c := got.Accounts[checking]
+		s := got.Accounts[savings]
+		all := got.Combined
+
+		if c[len(c)-1].Balance != test.wantChecking {
+			t.Logf("test %v wrong checking balance: got %v, want %v", i, c[len(c)-1].Balance, test.wantChecking)
+			t.Fail()
+		}
+
+		if s[len(s)-1].Balance != test.wantSavings {
+			t.Logf("test %v wrong savings balance: got %v, want %v", i, s[len(s)-1].Balance, test.wantSavings)
+			t.Fail()
+		}
+
+		if all[len(all)-1].Balance != test.wantCombined {
+			t.Logf("test %v wrong combined balance: got %v, want %v", i, all[len(all)-1].Balance, test.wantCombined)
+			t.Fail()
+		}
+	}
+
+	// duplicate and missing accounts
+	{
+		_, err := fpl.GetAccountResults([]fpl.TX{}, []fpl.Account{}, start, end, nil)
+		if err == nil {
+			t.Logf("expected error for no accounts")
+			t.FailNow()
+		}
+
+		_, err = fpl.GetAccountResults([]fpl.TX{}, []fpl.Account{{ID: "a"}, {ID: "a"}}, start, end, nil)
+		if err == nil {
+			t.Logf("expected error for duplicate accounts")
+			t.FailNow()
+		}
+	}
+}
+
+func TestGetAccountResultsCreditCard(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	const (
+		checking = "checking"
+		card     = "card"
+	)
+
+	// a $50 charge on the 5th of every month, with statements closing on the
+	// 25th and being paid 20 days later; the December statement is due after
+	// the end date, so there are only 11 payments
+	charges := []fpl.TX{{
+		Amount:      -5000,
+		Name:        "Streaming",
+		Active:      true,
+		Frequency:   fpl.MONTHLY,
+		Interval:    1,
+		StartsDay:   5,
+		StartsMonth: 1,
+		StartsYear:  2025,
+		Account:     card,
+	}}
+
+	tests := []struct {
+		card                                 fpl.Account
+		wantChecking, wantCard, wantCombined int
+		err                                  bool
+	}{
+		{
+			fpl.Account{ID: card, Type: fpl.CreditCard, StatementClosingDay: 25, PaymentDueDays: 20, FundingAccount: checking},
+			100000 - 11*5000,
+			-12*5000 + 11*5000,
+			100000 - 12*5000,
+			false,
+		},
+		{
+			fpl.Account{ID: card, Type: fpl.CreditCard, StatementClosingDay: 25, PaymentDueDays: 20, PaymentPolicy: fpl.MinimumPayment, MinimumPayment: 1000},
+			100000 - 11*1000,
+			-12*5000 + 11*1000,
+			100000 - 12*5000,
+			false,
+		},
+		{
+			fpl.Account{ID: card, Type: fpl.CreditCard, StatementClosingDay: 0, PaymentDueDays: 20, FundingAccount: checking},
+			0, 0, 0,
+			true,
+		},
+		{
+			fpl.Account{ID: card, Type: fpl.CreditCard, StatementClosingDay: 25, PaymentDueDays: 20, FundingAccount: card},
+			0, 0, 0,
+			true,
+		},
+		{
+			fpl.Account{ID: card, Type: fpl.CreditCard, StatementClosingDay: 25, PaymentDueDays: 20, PaymentPolicy: fpl.PayInFull},
+			100000 - 11*5000,
+			-12*5000 + 11*5000,
+			100000 - 12*5000,
+			false,
+		},
+		{
+			fpl.Account{ID: card, Type: fpl.CreditCard, StatementClosingDay: 25, PaymentDueDays: 20, FundingAccount: "nonexistent"},
+			0, 0, 0,
+			true,
+		},
+		{
+			fpl.Account{ID: card, Type: "credit_card", StatementClosingDay: 25, PaymentDueDays: 20},
+			0, 0, 0,
+			true,
+		},
+		{
+			fpl.Account{ID: card, Type: fpl.CreditCard, StatementClosingDay: 25, PaymentDueDays: 20, PaymentPolicy: "MINIMUM"},
+			0, 0, 0,
+			true,
+		},
+	}
+
+	for i, test := range tests {
+		accounts := []fpl.Account{{ID: checking, StartBalance: 100000}, test.card}
+
+		got, err := fpl.GetAccountResults(charges, accounts, start, end, nil)
+		if err != nil && !test.err {
+			t.Logf("test %v threw error when it wasn't supposed to: %v", i, err.Error())
+			t.FailNow()
+		} else if err != nil && test.err {
+			continue
+		} else if test.err {
+			t.Logf("test %v did not throw an error when it was supposed to", i)
+			t.FailNow()
+		}
+
+		ch := got.Accounts[checking]
+		cc := got.Accounts[card]
+		all := got.Combined
+
+		if ch[len(ch)-1].Balance != test.wantChecking {
+			t.Logf("test %v wrong checking balance: got %v, want %v", i, ch[len(ch)-1].Balance, test.wantChecking)
+			t.Fail()
+		}
+
+		if cc[len(cc)-1].Balance != test.wantCard {
+			t.Logf("test %v wrong card balance: got %v, want %v", i, cc[len(cc)-1].Balance, test.wantCard)
+			t.Fail()
+		}
+
+		if all[len(all)-1].Balance != test.wantCombined {
+			t.Logf("test %v wrong combined balance: got %v, want %v", i, all[len(all)-1].Balance, test.wantCombined)
+			t.Fail()
+		}
+
+		// the first payment is due on February 14th
+		feb14 := time.Date(2025, time.February, 14, 0, 0, 0, 0, time.UTC)
+		for _, r := range ch {
+			if r.Date.Equal(feb14) && r.DayNet >= 0 {
+				t.Logf("test %v expected a payment on %v, got %v", i, feb14, r.DayNet)
+				t.Fail()
+			}
+		}
+
+		// every statement's payment has its own ID
+		ids := make(map[string]bool)
+
+		for _, r := range cc {
+			for _, e := range r.DayEntries {
+				if e.Amount > 0 {
+					ids[e.ID] = true
+				}
+			}
+		}
+
+		if len(ids) != 11 || !ids["card-payment-2025-01-25"] {
+			t.Logf("test %v wrong payment ids: %v", i, ids)
+			t.Fail()
+		}
+	}
+
+	// a minimum payment of $0 isn't a payment at all
+	{
+		accounts := []fpl.Account{
+			{ID: checking, StartBalance: 100000},
+			{ID: card, Type: fpl.CreditCard, StatementClosingDay: 25, PaymentDueDays: 20, PaymentPolicy: fpl.MinimumPayment},
+		}
+
+		got, err := fpl.GetAccountResults(charges, accounts, start, end, nil)
+		if err != nil {
+			t.Logf("GetAccountResults with a $0 minimum payment failed: %v", err.Error())
+			t.FailNow()
+		}
+
+		for _, r := range got.Accounts[checking] {
+			if len(r.DayEntries) > 0 {
+				t.Logf("expected no payments, got %+v on %v", r.DayEntries, r.Date)
+				t.FailNow()
+			}
+		}
+	}
+
+	// statements close at midnight, so the results must start then too
+	{
+		accounts := []fpl.Account{{ID: checking}, tests[0].card}
+
+		_, err := fpl.GetAccountResults(charges, accounts, start.Add(time.Hour), end, nil)
+		if err == nil {
+			t.Logf("expected error for a start date that isn't at midnight")
+			t.FailNow()
+		}
+	}
+}
+
+func TestGetAccountResultsEntries(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+
+	accounts := []fpl.Account{{ID: "checking"}, {ID: "savings"}}
+
+	txs := []fpl.TX{{
+		ID:          "save",
+		Amount:      5000,
+		Name:        "Save",
+		Active:      true,
+		Frequency:   fpl.MONTHLY,
+		Interval:    1,
+		StartsDay:   1,
+		StartsMonth: 1,
+		StartsYear:  2025,
+		TransferTo:  "savings",
+	}}
+
+	got, err := fpl.GetAccountResults(txs, accounts, start, end, nil)
+	if err != nil {
+		t.Logf("GetAccountResults failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	tests := []struct {
+		account string
+		day     int
+		want    fpl.ResultEntry
+	}{
+		{"checking", 0, fpl.ResultEntry{ID: "save", Name: "Save", Amount: -5000, Occurrence: 0, Account: "checking"}},
+		{"savings", 0, fpl.ResultEntry{ID: "save", Name: "Save", Amount: 5000, Occurrence: 0, Account: "savings"}},
+		{"savings", 31, fpl.ResultEntry{ID: "save", Name: "Save", Amount: 5000, Occurrence: 1, Account: "savings"}},
+		{"checking", 59, fpl.ResultEntry{ID: "save", Name: "Save", Amount: -5000, Occurrence: 2, Account: "checking"}},
+	}
+
+	for i, test := range tests {
+		entries := got.Accounts[test.account][test.day].DayEntries
+		if len(entries) != 1 {
+			t.Logf("test %v failed: got %v entries but wanted 1", i, len(entries))
+			t.FailNow()
+		}
+
+		e := entries[0]
+		if e.ID != test.want.ID || e.Name != test.want.Name || e.Amount != test.want.Amount ||
+			e.Occurrence != test.want.Occurrence || e.Account != test.want.Account {
+			t.Logf("test %v failed: got %+v but wanted %+v", i, e, test.want)
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package fplib
+
+import (
+	"fmt"
+	"time"
+)
+
+// AggregateOptions controls how Aggregate buckets results into periods.
+type AggregateOptions struct {
+	// When false, periods are aligned to the calendar: weeks start on
+	// WeekStart, months on the 1st, quarters in January/April/July/October
+	// and years on January 1st. When true, periods are rolling, and start on
+	// the date of the first result and every 7 days, 1, 3 or 12 months after
+	// it. Months that are too short to have the first result's day of the
+	// month start on their last day instead, so a rolling month that starts
+	// on January 31st is followed by ones starting on February 28th and
+	// March 31st.
+	Rolling bool
+	// The first day of the week for calendar-aligned WEEKLY periods.
+	WeekStart time.Weekday
+}
+
+// PeriodResult summarizes all of the results within a single period.
+type PeriodResult struct {
+	// The first and last days of the results within this period.
+	Start time.Time
+	End   time.Time
+	// The number of results within this period.
+	Days int
+	// The balance before any of the transactions in this period, and the
+	// balance after all of them.
+	OpeningBalance int
+	ClosingBalance int
+	MinBalance     int
+	MaxBalance     int
+	Income         int
+	Expenses       int
+	Net            int
+}
+
+// Aggregate buckets daily results into WEEKLY, MONTHLY, QUARTERLY or YEARLY
+// periods, which is more practical than daily results for long projections.
+// The results must be sorted by date, as they are by GetResults.
+func Aggregate(results []Result, period string, opts AggregateOptions) ([]PeriodResult, error) {
+	var step func(t time.Time, n int) time.Time
+
+	switch period {
+	case WEEKLY:
+		step = func(t time.Time, n int) time.Time { return t.AddDate(0, 0, 7*n) }
+	case MONTHLY:
+		step = func(t time.Time, n int) time.Time { return addMonths(t, n) }
+	case QUARTERLY:
+		step = func(t time.Time, n int) time.Time { return addMonths(t, 3*n) }
+	case YEARLY:
+		step = func(t time.Time, n int) time.Time { return addMonths(t, 12*n) }
+	default:
+		return []PeriodResult{}, fmt.Errorf("unsupported aggregation period: %v", period)
+	}
+
+	periods := []PeriodResult{}
+
+	if len(results) == 0 {
+		return periods, nil
+	}
+
+	first := results[0].Date
+	// the number of rolling periods since the first result
+	n := 0
+	// the start of the next period; results on or after it go in a new one
+	var next time.Time
+
+	for _, r := range results {
+		if len(periods) == 0 || !r.Date.Before(next) {
+			if opts.Rolling {
+				for !r.Date.Before(step(first, n+1)) {
+					n++
+				}
+
+				next = step(first, n+1)
+			} else {
+				next = step(getPeriodStart(r.Date, period, opts.WeekStart), 1)
+			}
+
+			opening := r.Balance - r.DayNet
+			periods = append(periods, PeriodResult{
+				Start:          r.Date,
+				OpeningBalance: opening,
+				MinBalance:     r.Balance,
+				MaxBalance:     r.Balance,
+			})
+		}
+
+		p := &periods[len(periods)-1]
+		p.End = r.Date
+		p.Days++
+		p.ClosingBalance = r.Balance
+		p.MinBalance = min(p.MinBalance, r.Balance)
+		p.MaxBalance = max(p.MaxBalance, r.Balance)
+		p.Income += r.DayIncome
+		p.Expenses += r.DayExpenses
+		p.Net += r.DayNet
+	}
+
+	return periods, nil
+}
+
+// getPeriodStart returns midnight on the first day of the calendar period
+// that contains t.
+func getPeriodStart(t time.Time, period string, weekStart time.Weekday) time.Time {
+	y, m, d := t.Date()
+
+	switch period {
+	case WEEKLY:
+		offset := (int(t.Weekday()) - int(weekStart) + 7) % 7
+
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location())
+	case QUARTERLY:
+		return time.Date(y, m-(m-1)%3, 1, 0, 0, 0, 0, t.Location())
+	case YEARLY:
+		return time.Date(y, time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// addMonths returns the same day and time as t, the provided number of months
+// later. Unlike t.AddDate, the day is clamped to the last day of the target
+// month rather than overflowing into the month after it.
+func addMonths(t time.Time, months int) time.Time {
+	y, m, d := t.Date()
+	// the zeroth day of the following month is the last day of this month
+	lastDay := time.Date(y, m+time.Month(months)+1, 0, 0, 0, 0, 0, t.Location()).Day()
+
+	return time.Date(y, m+time.Month(months), min(d, lastDay), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
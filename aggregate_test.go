@@ -0,0 +1,170 @@
+package fplib_test
+
+import (
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+
+	txs := []fpl.TX{
+		{
+			Amount:      -10000,
+			Name:        "Rent",
+			Active:      true,
+			Frequency:   fpl.MONTHLY,
+			Interval:    1,
+			StartsDay:   1,
+			StartsMonth: 1,
+			StartsYear:  2025,
+		},
+		{
+			Amount:      3000,
+			Name:        "Side gig",
+			Active:      true,
+			Frequency:   fpl.MONTHLY,
+			Interval:    1,
+			StartsDay:   20,
+			StartsMonth: 1,
+			StartsYear:  2025,
+		},
+	}
+
+	// January 1st, 2025 is a Wednesday
+	results, err := fpl.GetResults(txs, date(2025, 1, 1), date(2025, 12, 31), 100000, nil)
+	if err != nil {
+		t.Logf("GetResults failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	tests := []struct {
+		period string
+		opts   fpl.AggregateOptions
+		// The number of periods to expect.
+		count int
+		// The first and last periods to expect.
+		first, last fpl.PeriodResult
+	}{
+		{
+			fpl.MONTHLY,
+			fpl.AggregateOptions{},
+			12,
+			fpl.PeriodResult{date(2025, 1, 1), date(2025, 1, 31), 31, 100000, 93000, 90000, 93000, 3000, -10000, -7000},
+			fpl.PeriodResult{date(2025, 12, 1), date(2025, 12, 31), 31, 23000, 16000, 13000, 16000, 3000, -10000, -7000},
+		},
+		{
+			fpl.QUARTERLY,
+			fpl.AggregateOptions{},
+			4,
+			fpl.PeriodResult{date(2025, 1, 1), date(2025, 3, 31), 90, 100000, 79000, 76000, 93000, 9000, -30000, -21000},
+			fpl.PeriodResult{date(2025, 10, 1), date(2025, 12, 31), 92, 37000, 16000, 13000, 30000, 9000, -30000, -21000},
+		},
+		{
+			fpl.YEARLY,
+			fpl.AggregateOptions{},
+			1,
+			fpl.PeriodResult{date(2025, 1, 1), date(2025, 12, 31), 365, 100000, 16000, 13000, 93000, 36000, -120000, -84000},
+			fpl.PeriodResult{date(2025, 1, 1), date(2025, 12, 31), 365, 100000, 16000, 13000, 93000, 36000, -120000, -84000},
+		},
+		{
+			fpl.WEEKLY,
+			fpl.AggregateOptions{WeekStart: time.Monday},
+			53,
+			fpl.PeriodResult{date(2025, 1, 1), date(2025, 1, 5), 5, 100000, 90000, 90000, 90000, 0, -10000, -10000},
+			fpl.PeriodResult{date(2025, 12, 29), date(2025, 12, 31), 3, 16000, 16000, 16000, 16000, 0, 0, 0},
+		},
+		{
+			fpl.WEEKLY,
+			fpl.AggregateOptions{Rolling: true},
+			53,
+			fpl.PeriodResult{date(2025, 1, 1), date(2025, 1, 7), 7, 100000, 90000, 90000, 90000, 0, -10000, -10000},
+			fpl.PeriodResult{date(2025, 12, 31), date(2025, 12, 31), 1, 16000, 16000, 16000, 16000, 0, 0, 0},
+		},
+	}
+
+	for i, test := range tests {
+		got, err := fpl.Aggregate(results, test.period, test.opts)
+		if err != nil {
+			t.Logf("test %v threw error when it wasn't supposed to: %v", i, err.Error())
+			t.FailNow()
+		}
+
+		if len(got) != test.count {
+			t.Logf("test %v wrong period count: got %v, want %v", i, len(got), test.count)
+			t.FailNow()
+		}
+
+		if got[0] != test.first {
+			t.Logf("test %v wrong first period: got %+v, want %+v", i, got[0], test.first)
+			t.Fail()
+		}
+
+		if got[len(got)-1] != test.last {
+			t.Logf("test %v wrong last period: got %+v, want %+v", i, got[len(got)-1], test.last)
+			t.Fail()
+		}
+	}
+
+	// rolling monthly periods start on the first result
+	{
+		got, err := fpl.Aggregate(results[14:], fpl.MONTHLY, fpl.AggregateOptions{Rolling: true})
+		if err != nil {
+			t.Logf("rolling monthly threw error: %v", err.Error())
+			t.FailNow()
+		}
+
+		if len(got) != 12 || !got[0].Start.Equal(date(2025, 1, 15)) || !got[0].End.Equal(date(2025, 2, 14)) || !got[11].Start.Equal(date(2025, 12, 15)) {
+			t.Logf("rolling monthly failed: got %v periods, first %+v", len(got), got[0])
+			t.FailNow()
+		}
+	}
+
+	// rolling periods from the end of a month start on the last day of
+	// shorter months rather than overflowing into the month after them
+	{
+		want := []time.Time{date(2025, 1, 31), date(2025, 2, 28), date(2025, 3, 31), date(2025, 4, 30), date(2025, 5, 31), date(2025, 6, 30)}
+
+		got, err := fpl.Aggregate(results[30:], fpl.MONTHLY, fpl.AggregateOptions{Rolling: true})
+		if err != nil {
+			t.Logf("rolling monthly from the end of the month threw error: %v", err.Error())
+			t.FailNow()
+		}
+
+		if len(got) != 12 || !got[0].End.Equal(date(2025, 2, 27)) || !got[1].End.Equal(date(2025, 3, 30)) {
+			t.Logf("rolling monthly from the end of the month failed: got %v periods, first %+v", len(got), got[0])
+			t.FailNow()
+		}
+
+		for i, start := range want {
+			if !got[i].Start.Equal(start) {
+				t.Logf("rolling monthly period %v starts on %v, want %v", i, got[i].Start, start)
+				t.Fail()
+			}
+		}
+
+		got, err = fpl.Aggregate(results[30:], fpl.QUARTERLY, fpl.AggregateOptions{Rolling: true})
+		if err != nil || len(got) != 4 || !got[1].Start.Equal(date(2025, 4, 30)) || !got[2].Start.Equal(date(2025, 7, 31)) {
+			t.Logf("rolling quarterly from the end of the month failed: got %+v (%v)", got, err)
+			t.FailNow()
+		}
+	}
+
+	// invalid input
+	{
+		_, err := fpl.Aggregate(results, "nonsense", fpl.AggregateOptions{})
+		if err == nil {
+			t.Logf("expected error for unsupported period")
+			t.FailNow()
+		}
+
+		got, err := fpl.Aggregate([]fpl.Result{}, fpl.MONTHLY, fpl.AggregateOptions{})
+		if err != nil || len(got) != 0 {
+			t.Logf("expected no periods for no results")
+			t.FailNow()
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package fplib_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// getBenchmarkTXs returns n active transactions with a mix of monthly, weekly
+// and yearly recurrences, roughly resembling a large real-world budget.
+func getBenchmarkTXs(n int) []fpl.TX {
+	txs := make([]fpl.TX, n)
+
+	for i := range txs {
+		txs[i] = fpl.TX{
+			ID:          fmt.Sprintf("tx-%v", i),
+			Name:        fmt.Sprintf("Transaction %v", i),
+			Amount:      (i%200 - 150) * 100,
+			Active:      true,
+			Frequency:   fpl.MONTHLY,
+			Interval:    1,
+			StartsDay:   i%28 + 1,
+			StartsMonth: 1,
+			StartsYear:  2025,
+		}
+
+		switch i % 10 {
+		case 0:
+			txs[i].Frequency = fpl.WEEKLY
+			txs[i].Weekdays = map[int]bool{i % 7: true}
+		case 1, 2, 3:
+			txs[i].Frequency = fpl.YEARLY
+			txs[i].StartsMonth = i%12 + 1
+		}
+	}
+
+	return txs
+}
+
+func benchmarkGetResults(b *testing.B, n int, years int) {
+	b.Helper()
+
+	txs := getBenchmarkTXs(n)
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(years, 0, -1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for range b.N {
+		_, err := fpl.GetResults(txs, start, end, 0, nil)
+		if err != nil {
+			b.Logf("GetResults failed: %v", err.Error())
+			b.FailNow()
+		}
+	}
+}
+
+func BenchmarkGetResults100TXs1Year(b *testing.B) {
+	benchmarkGetResults(b, 100, 1)
+}
+
+func BenchmarkGetResults1kTXs10Years(b *testing.B) {
+	benchmarkGetResults(b, 1000, 10)
+}
+
+func BenchmarkGetResults10kTXs30Years(b *testing.B) {
+	benchmarkGetResults(b, 10000, 30)
+}
+
+func BenchmarkProjectionUpdateTX1kTXs10Years(b *testing.B) {
+	txs := getBenchmarkTXs(1000)
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(10, 0, -1)
+
+	p, err := fpl.NewProjection(txs, start, end, 0)
+	if err != nil {
+		b.Logf("NewProjection failed: %v", err.Error())
+		b.FailNow()
+	}
+
+	tx := txs[500]
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := range b.N {
+		tx.Amount = i
+
+		err := p.UpdateTX(tx)
+		if err != nil {
+			b.Logf("UpdateTX failed: %v", err.Error())
+			b.FailNow()
+		}
+	}
+}
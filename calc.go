@@ -1,6 +1,10 @@
 package fplib
 
-import "math"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 const (
 	// Float representation of months in a year.
@@ -45,3 +49,127 @@ func CalculateDailyRate(amount int, days int) int {
 	// (dollars) / (1 day) => (dollars) / (400 days)
 	return int(math.Round(float64(amount) / (float64(days))))
 }
+
+// CalculateRate divides an amount that accumulated over the provided number of
+// periods (such as months or years, which may be fractional) into an integer
+// rate per period that represents a dollar amount ($100.00 = 10000).
+func CalculateRate(amount int, periods float64) int {
+	return int(math.Round(float64(amount) / periods))
+}
+
+// CountMonths returns the number of months spanned by the days from start to
+// end, inclusive, according to the provided day count convention:
+//
+//   - Actual36525: actual days / (365.25 / 12), as in CalculateMonthlyRate.
+//   - Actual365: actual days / (365 / 12).
+//   - Actual360: actual days / 30.
+//   - Thirty360: days counted as if every month had 30 days, / 30.
+//   - Calendar: each calendar month counts as one, and partial months count
+//     as the fraction of their days that are included, so that February
+//     isn't under-counted and a 31-day month isn't over-counted.
+func CountMonths(start, end time.Time, convention string) (float64, error) {
+	days := float64(countDays(start, end))
+
+	switch convention {
+	case Actual36525:
+		return days / (yrf / mof), nil
+	case Actual365:
+		return days / (365 / mof), nil
+	case Actual360:
+		return days / 30, nil
+	case Thirty360:
+		return float64(countDays30360(start, end)) / 30, nil
+	case Calendar:
+		return countCalendarPeriods(start, end, func(t time.Time) time.Time {
+			return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		}, func(t time.Time) time.Time {
+			return t.AddDate(0, 1, 0)
+		}), nil
+	default:
+		return 0, fmt.Errorf("unsupported day count convention: %v", convention)
+	}
+}
+
+// CountYears returns the number of years spanned by the days from start to
+// end, inclusive, according to the provided day count convention. See
+// CountMonths for the supported conventions; with Calendar, partial years
+// count as the fraction of their days (365 or 366) that are included.
+func CountYears(start, end time.Time, convention string) (float64, error) {
+	days := float64(countDays(start, end))
+
+	switch convention {
+	case Actual36525:
+		return days / yrf, nil
+	case Actual365:
+		return days / 365, nil
+	case Actual360:
+		return days / 360, nil
+	case Thirty360:
+		return float64(countDays30360(start, end)) / 360, nil
+	case Calendar:
+		return countCalendarPeriods(start, end, func(t time.Time) time.Time {
+			return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+		}, func(t time.Time) time.Time {
+			return t.AddDate(1, 0, 0)
+		}), nil
+	default:
+		return 0, fmt.Errorf("unsupported day count convention: %v", convention)
+	}
+}
+
+// toUTCDate drops the time of day and time zone of t, so that days can be
+// counted without being affected by daylight saving time.
+func toUTCDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// countDays returns the number of days from start to end, inclusive.
+func countDays(start, end time.Time) int {
+	return int(toUTCDate(end).Sub(toUTCDate(start)).Hours()/HoursInDay) + 1
+}
+
+// countDays30360 returns the number of days from start to end, inclusive,
+// using the 30/360 (US) convention where every month has 30 days.
+func countDays30360(start, end time.Time) int {
+	// the day after the end makes the count inclusive
+	y1, m1, d1 := toUTCDate(start).Date()
+	y2, m2, d2 := toUTCDate(end).AddDate(0, 0, 1).Date()
+
+	if d1 == 31 {
+		d1 = 30
+	}
+
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+
+	return 360*(y2-y1) + 30*(int(m2)-int(m1)) + (d2 - d1)
+}
+
+// countCalendarPeriods adds up the fraction of every calendar period (such as
+// a month or a year) that the days from start to end, inclusive, cover.
+// periodStart returns the start of the period that contains a date, and next
+// returns the start of the following period.
+func countCalendarPeriods(start, end time.Time, periodStart, next func(t time.Time) time.Time) float64 {
+	start = toUTCDate(start)
+	end = toUTCDate(end).AddDate(0, 0, 1)
+	periods := float64(0)
+
+	for ps := periodStart(start); ps.Before(end); ps = next(ps) {
+		pe := next(ps)
+		from := ps
+		to := pe
+
+		if start.After(from) {
+			from = start
+		}
+
+		if end.Before(to) {
+			to = end
+		}
+
+		periods += to.Sub(from).Hours() / pe.Sub(ps).Hours()
+	}
+
+	return periods
+}
@@ -1,7 +1,9 @@
 package fplib_test
 
 import (
+	"math"
 	"testing"
+	"time"
 
 	fpl "github.com/charles-m-knox/finance-planner-lib"
 )
@@ -73,3 +75,70 @@ func TestCalculateDailyRate(t *testing.T) {
 		}
 	}
 }
+
+func TestCountMonths(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		start, end time.Time
+		convention string
+		want       float64
+		err        bool
+	}{
+		{date(2025, 2, 1), date(2025, 2, 28), fpl.Calendar, 1, false},
+		{date(2025, 2, 1), date(2025, 2, 28), fpl.Actual36525, 28 / (365.25 / 12), false},
+		{date(2025, 2, 1), date(2025, 2, 28), fpl.Actual365, 28 / (365.0 / 12), false},
+		{date(2025, 2, 1), date(2025, 2, 28), fpl.Actual360, 28.0 / 30, false},
+		{date(2025, 2, 1), date(2025, 2, 28), fpl.Thirty360, 1, false},
+		{date(2025, 1, 1), date(2025, 12, 31), fpl.Calendar, 12, false},
+		{date(2025, 1, 1), date(2025, 12, 31), fpl.Actual365, 12, false},
+		{date(2025, 1, 1), date(2025, 12, 31), fpl.Thirty360, 12, false},
+		{date(2025, 1, 16), date(2025, 2, 14), fpl.Calendar, 16.0/31 + 14.0/28, false},
+		{date(2025, 1, 1), date(2025, 1, 1), "nonsense", 0, true},
+	}
+
+	for i, test := range tests {
+		got, err := fpl.CountMonths(test.start, test.end, test.convention)
+		if (err != nil) != test.err {
+			t.Logf("test %v failed: got error %v", i, err)
+			t.FailNow()
+		}
+
+		if math.Abs(got-test.want) > 1e-9 {
+			t.Logf("test %v failed: got %v but wanted %v", i, got, test.want)
+			t.FailNow()
+		}
+	}
+}
+
+func TestCountYears(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		start, end time.Time
+		convention string
+		want       float64
+		err        bool
+	}{
+		{date(2024, 1, 1), date(2024, 12, 31), fpl.Calendar, 1, false},
+		{date(2024, 1, 1), date(2024, 12, 31), fpl.Actual365, 366.0 / 365, false},
+		{date(2024, 1, 1), date(2024, 12, 31), fpl.Actual36525, 366 / 365.25, false},
+		{date(2024, 1, 1), date(2024, 12, 31), fpl.Actual360, 366.0 / 360, false},
+		{date(2024, 1, 1), date(2024, 12, 31), fpl.Thirty360, 1, false},
+		{date(2024, 7, 1), date(2025, 6, 30), fpl.Calendar, 184.0/366 + 181.0/365, false},
+		{date(2024, 1, 1), date(2024, 1, 1), "nonsense", 0, true},
+	}
+
+	for i, test := range tests {
+		got, err := fpl.CountYears(test.start, test.end, test.convention)
+		if (err != nil) != test.err {
+			t.Logf("test %v failed: got error %v", i, err)
+			t.FailNow()
+		}
+
+		if math.Abs(got-test.want) > 1e-9 {
+			t.Logf("test %v failed: got %v but wanted %v", i, got, test.want)
+			t.FailNow()
+		}
+	}
+}
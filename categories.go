@@ -0,0 +1,88 @@
+package fplib
+
+// CategoryStats holds the totals and rates for every transaction occurrence
+// that shares a category or tag over a set of results. The rates are
+// calculated the same way as they are in CalculateStats.
+type CategoryStats struct {
+	// The number of transaction occurrences.
+	Count    int
+	Income   int
+	Expenses int
+	Net      int
+
+	DailySpending   int
+	DailyIncome     int
+	DailyNet        int
+	MonthlySpending int
+	MonthlyIncome   int
+	MonthlyNet      int
+	YearlySpending  int
+	YearlyIncome    int
+	YearlyNet       int
+}
+
+// CalculateCategoryStats groups every transaction occurrence in the provided
+// results by its category (see TX.Category) and calculates the totals and
+// rates of each. Uncategorized transactions are grouped under the empty
+// string.
+func CalculateCategoryStats(results []Result) map[string]CategoryStats {
+	return calculateGroupedStats(results, func(e *ResultEntry) []string {
+		return []string{e.Category}
+	})
+}
+
+// CalculateTagStats groups every transaction occurrence in the provided
+// results by each of its tags (see TX.Tags) and calculates the totals and
+// rates of each. A transaction with several tags counts towards all of them,
+// and untagged transactions are not included.
+func CalculateTagStats(results []Result) map[string]CategoryStats {
+	return calculateGroupedStats(results, func(e *ResultEntry) []string {
+		return e.Tags
+	})
+}
+
+// calculateGroupedStats adds up every entry in the provided results under each
+// of the keys returned by groups for that entry.
+func calculateGroupedStats(results []Result, groups func(e *ResultEntry) []string) map[string]CategoryStats {
+	stats := make(map[string]CategoryStats)
+
+	for i := range results {
+		for j := range results[i].DayEntries {
+			e := &results[i].DayEntries[j]
+
+			for _, key := range groups(e) {
+				s := stats[key]
+				s.Count++
+				s.Net += e.Amount
+
+				if e.Amount >= 0 {
+					s.Income += e.Amount
+				} else {
+					s.Expenses += e.Amount
+				}
+
+				stats[key] = s
+			}
+		}
+	}
+
+	count := len(results)
+	if count <= 1 {
+		return stats
+	}
+
+	for key, s := range stats {
+		s.DailySpending = CalculateDailyRate(s.Expenses, count)
+		s.DailyIncome = CalculateDailyRate(s.Income, count)
+		s.DailyNet = s.DailySpending + s.DailyIncome
+		s.MonthlySpending = CalculateMonthlyRate(s.Expenses, count)
+		s.MonthlyIncome = CalculateMonthlyRate(s.Income, count)
+		s.MonthlyNet = s.MonthlySpending + s.MonthlyIncome
+		s.YearlySpending = CalculateYearlyRate(s.Expenses, count)
+		s.YearlyIncome = CalculateYearlyRate(s.Income, count)
+		s.YearlyNet = s.YearlySpending + s.YearlyIncome
+		stats[key] = s
+	}
+
+	return stats
+}
@@ -0,0 +1,80 @@
+package fplib_test
+
+import (
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestCalculateCategoryStats(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	monthly := func(name string, amount int, category string, tags ...string) fpl.TX {
+		tx := monthlyTX("", name, amount, 1)
+		tx.Category = category
+		tx.Tags = tags
+
+		return tx
+	}
+
+	txs := []fpl.TX{
+		monthly("Music", -1000, "Subscriptions", "entertainment"),
+		monthly("Video", -2000, "Subscriptions", "entertainment", "shared"),
+		monthly("Rent", -100000, "Housing", "shared"),
+		monthly("Paycheck", 300000, ""),
+	}
+
+	results, err := fpl.GetResults(txs, start, end, 0, nil)
+	if err != nil {
+		t.Logf("GetResults failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	for _, r := range results {
+		if len(r.DayEntries) != len(r.DayTransactionNamesSlice) {
+			t.Logf("entries mismatch on %v: got %v, want %v", r.Date, len(r.DayEntries), len(r.DayTransactionNamesSlice))
+			t.FailNow()
+		}
+	}
+
+	tests := []struct {
+		got  map[string]fpl.CategoryStats
+		key  string
+		want fpl.CategoryStats
+	}{
+		{fpl.CalculateCategoryStats(results), "Subscriptions", fpl.CategoryStats{Count: 24, Expenses: -36000, Net: -36000, YearlySpending: -36025, YearlyNet: -36025}},
+		{fpl.CalculateCategoryStats(results), "Housing", fpl.CategoryStats{Count: 12, Expenses: -1200000, Net: -1200000, YearlySpending: -1200822, YearlyNet: -1200822}},
+		{fpl.CalculateCategoryStats(results), "", fpl.CategoryStats{Count: 12, Income: 3600000, Net: 3600000, YearlyIncome: 3602466, YearlyNet: 3602466}},
+		{fpl.CalculateTagStats(results), "entertainment", fpl.CategoryStats{Count: 24, Expenses: -36000, Net: -36000, YearlySpending: -36025, YearlyNet: -36025}},
+		{fpl.CalculateTagStats(results), "shared", fpl.CategoryStats{Count: 24, Expenses: -1224000, Net: -1224000, YearlySpending: -1224838, YearlyNet: -1224838}},
+	}
+
+	for i, test := range tests {
+		got := test.got[test.key]
+		if got.Count != test.want.Count ||
+			got.Income != test.want.Income ||
+			got.Expenses != test.want.Expenses ||
+			got.Net != test.want.Net ||
+			got.YearlySpending != test.want.YearlySpending ||
+			got.YearlyIncome != test.want.YearlyIncome ||
+			got.YearlyNet != test.want.YearlyNet {
+			t.Logf("test %v failed: got %+v but wanted %+v", i, got, test.want)
+			t.FailNow()
+		}
+	}
+
+	if _, ok := fpl.CalculateTagStats(results)[""]; ok {
+		t.Logf("untagged transactions should not be grouped")
+		t.FailNow()
+	}
+
+	if got := fpl.CalculateCategoryStats(results[:1])["Housing"]; got.Count != 1 || got.YearlyNet != 0 {
+		t.Logf("single result failed: got %+v", got)
+		t.FailNow()
+	}
+}
@@ -0,0 +1,91 @@
+package fplib
+
+import (
+	"sort"
+	"time"
+)
+
+// Checkpoint is a known, real balance at the end of a specific day, such as
+// "on 2026-10-01 the real balance was $3,210.55".
+type Checkpoint struct {
+	Date    time.Time `yaml:"date"`
+	Balance int       `yaml:"balance"` // in cents; 500 = $5.00
+}
+
+// CheckpointVariance compares a checkpoint's real balance against the
+// projected balance for that day.
+type CheckpointVariance struct {
+	Date time.Time
+	// The projected balance, based on the start balance or the previous
+	// checkpoint, whichever is more recent.
+	Projected int
+	Actual    int
+	// Actual minus Projected.
+	Variance int
+}
+
+// GetResultsWithCheckpoints is like GetResults, but the running balance is
+// reset to the real balance of each checkpoint on its date. See
+// ApplyCheckpoints.
+func GetResultsWithCheckpoints(tx []TX, startDate time.Time, endDate time.Time, startBalance int, checkpoints []Checkpoint, statusHook func(status string)) ([]Result, []CheckpointVariance, error) {
+	results, err := GetResults(tx, startDate, endDate, startBalance, statusHook)
+	if err != nil {
+		return results, []CheckpointVariance{}, err
+	}
+
+	return results, ApplyCheckpoints(results, checkpoints), nil
+}
+
+// ApplyCheckpoints rebases the balances of the provided results on each
+// checkpoint's real balance, so that the balance on a checkpoint's date (and
+// every balance after it) reflects what is actually in the bank. It returns
+// the variance between the projected and real balance at each checkpoint, in
+// date order. Checkpoints on days that aren't in the results are ignored.
+//
+// Only Balance is modified; the other fields, such as DiffFromStart, still
+// only reflect the transactions.
+func ApplyCheckpoints(results []Result, checkpoints []Checkpoint) []CheckpointVariance {
+	variances := []CheckpointVariance{}
+
+	sorted := append([]Checkpoint{}, checkpoints...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	dayIndex := make(map[string]int, len(results))
+	for i, r := range results {
+		dayIndex[GetNowDateString(r.Date)] = i
+	}
+
+	// the index of the next result whose balance needs the offset applied
+	next := 0
+	offset := 0
+
+	for _, c := range sorted {
+		i, ok := dayIndex[GetNowDateString(c.Date)]
+		if !ok {
+			continue
+		}
+
+		for ; next <= i; next++ {
+			results[next].Balance += offset
+		}
+
+		v := CheckpointVariance{
+			Date:      results[i].Date,
+			Projected: results[i].Balance,
+			Actual:    c.Balance,
+			Variance:  c.Balance - results[i].Balance,
+		}
+
+		variances = append(variances, v)
+		offset += v.Variance
+		results[i].Balance = c.Balance
+	}
+
+	for ; next < len(results); next++ {
+		results[next].Balance += offset
+	}
+
+	return variances
+}
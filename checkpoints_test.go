@@ -0,0 +1,91 @@
+package fplib_test
+
+import (
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestGetResultsWithCheckpoints(t *testing.T) {
+	t.Parallel()
+
+	txs := []fpl.TX{{
+		Amount:      -10000,
+		Name:        "Rent",
+		Active:      true,
+		Frequency:   fpl.MONTHLY,
+		Interval:    1,
+		StartsDay:   1,
+		StartsMonth: 1,
+		StartsYear:  2025,
+	}}
+
+	start, end := date(2025, 1, 1), date(2025, 12, 31)
+
+	checkpoints := []fpl.Checkpoint{
+		// intentionally out of order
+		{Date: date(2025, 6, 15), Balance: 40000},
+		{Date: date(2025, 3, 10), Balance: 75000},
+		// outside of the window
+		{Date: date(2026, 1, 1), Balance: 0},
+	}
+
+	results, variances, err := fpl.GetResultsWithCheckpoints(txs, start, end, 100000, checkpoints, nil)
+	if err != nil {
+		t.Logf("GetResultsWithCheckpoints failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	wantVariances := []fpl.CheckpointVariance{
+		// 3 rent payments by March 10th
+		{date(2025, 3, 10), 70000, 75000, 5000},
+		// 3 more rent payments since then
+		{date(2025, 6, 15), 45000, 40000, -5000},
+	}
+
+	if len(variances) != len(wantVariances) {
+		t.Logf("wrong variance count: got %v, want %v", len(variances), len(wantVariances))
+		t.FailNow()
+	}
+
+	for i, want := range wantVariances {
+		if variances[i] != want {
+			t.Logf("variance %v failed: got %+v, want %+v", i, variances[i], want)
+			t.FailNow()
+		}
+	}
+
+	tests := []struct {
+		date time.Time
+		want int
+	}{
+		{date(2025, 3, 9), 70000},
+		{date(2025, 3, 10), 75000},
+		{date(2025, 4, 1), 65000},
+		{date(2025, 6, 15), 40000},
+		{date(2025, 12, 31), -20000},
+	}
+
+	for i, test := range tests {
+		for _, r := range results {
+			if r.Date.Equal(test.date) && r.Balance != test.want {
+				t.Logf("test %v failed: got %v on %v, want %v", i, r.Balance, test.date, test.want)
+				t.FailNow()
+			}
+		}
+	}
+
+	// the differences from the start balance only reflect transactions
+	if got := results[len(results)-1].DiffFromStart; got != -120000 {
+		t.Logf("wrong DiffFromStart: got %v, want %v", got, -120000)
+		t.FailNow()
+	}
+
+	_, _, err = fpl.GetResultsWithCheckpoints(txs, end, start, 0, checkpoints, nil)
+	if err == nil {
+		t.Logf("expected error for start after end")
+		t.FailNow()
+	}
+}
@@ -4,6 +4,7 @@ const (
 	WEEKLY                    string = "WEEKLY"
 	MONTHLY                   string = "MONTHLY"
 	YEARLY                    string = "YEARLY"
+	QUARTERLY                 string = "QUARTERLY"
 	New                       string = "New"
 	None                      string = "none"
 	Desc                      string = "Desc"
@@ -11,4 +12,23 @@ const (
 	DaysInMonth                      = 31
 	HoursInDay                       = 24
 	DefaultTransactionBalance        = 500
+	UNIFORM                   string = "UNIFORM"
+	NORMAL                    string = "NORMAL"
+	EMPIRICAL                 string = "EMPIRICAL"
+	CreditCard                string = "CREDIT_CARD"
+	PayInFull                 string = "PAY_IN_FULL"
+	MinimumPayment            string = "MINIMUM_PAYMENT"
+	Actual36525               string = "ACTUAL/365.25"
+	Actual365                 string = "ACTUAL/365"
+	Actual360                 string = "ACTUAL/360"
+	Thirty360                 string = "30/360"
+	Calendar                  string = "CALENDAR"
+	PhasePreparing            string = "PREPARING"
+	PhaseRecurrences          string = "RECURRENCES"
+	PhaseCalculating          string = "CALCULATING"
+	PhaseDone                 string = "DONE"
+	PhaseScenarios            string = "SCENARIOS"
+	PhaseAccounts             string = "ACCOUNTS"
+	DefaultProgressInterval          = 1000
+	TXFileVersion                    = 1
 )
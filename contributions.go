@@ -0,0 +1,62 @@
+package fplib
+
+import "time"
+
+// Contribution is how much a single transaction definition contributed over a
+// set of results.
+type Contribution struct {
+	ID    string
+	Name  string
+	Total int
+	// The number of times the transaction occurred.
+	Count int
+	// The first and last occurrences of the transaction.
+	First time.Time
+	Last  time.Time
+	// The share, from 0 to 1, of total income (for transactions that
+	// contributed income) or total expenses (for transactions that
+	// contributed expenses) that this transaction accounts for.
+	Share float64
+}
+
+// GetContributions reports how much each transaction definition contributed
+// over the provided results, keyed by TX.ID. Transactions without an ID are
+// grouped together under the empty string.
+func GetContributions(results []Result) map[string]Contribution {
+	contributions := make(map[string]Contribution)
+	income := 0
+	expenses := 0
+
+	for _, r := range results {
+		for _, e := range r.DayEntries {
+			c, ok := contributions[e.ID]
+			if !ok {
+				c = Contribution{ID: e.ID, Name: e.Name, First: r.Date}
+			}
+
+			c.Total += e.Amount
+			c.Count++
+			c.Last = r.Date
+			contributions[e.ID] = c
+
+			if e.Amount >= 0 {
+				income += e.Amount
+			} else {
+				expenses += e.Amount
+			}
+		}
+	}
+
+	for id, c := range contributions {
+		switch {
+		case c.Total > 0 && income != 0:
+			c.Share = float64(c.Total) / float64(income)
+		case c.Total < 0 && expenses != 0:
+			c.Share = float64(c.Total) / float64(expenses)
+		}
+
+		contributions[id] = c
+	}
+
+	return contributions
+}
@@ -0,0 +1,53 @@
+package fplib_test
+
+import (
+	"math"
+	"testing"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+func TestGetContributions(t *testing.T) {
+	t.Parallel()
+
+	txs := []fpl.TX{
+		monthlyTX("rent", "Rent", -30000, 1),
+		// the same name as another transaction must not be merged with it
+		monthlyTX("rent2", "Rent", -10000, 15),
+		monthlyTX("pay", "Paycheck", 50000, 10),
+	}
+
+	results, err := fpl.GetResults(txs, date(2025, 1, 1), date(2025, 6, 30), 0, nil)
+	if err != nil {
+		t.Logf("GetResults failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	got := fpl.GetContributions(results)
+
+	tests := []struct {
+		want fpl.Contribution
+	}{
+		{fpl.Contribution{"rent", "Rent", -180000, 6, date(2025, 1, 1), date(2025, 6, 1), 0.75}},
+		{fpl.Contribution{"rent2", "Rent", -60000, 6, date(2025, 1, 15), date(2025, 6, 15), 0.25}},
+		{fpl.Contribution{"pay", "Paycheck", 300000, 6, date(2025, 1, 10), date(2025, 6, 10), 1}},
+	}
+
+	if len(got) != len(tests) {
+		t.Logf("wrong contribution count: got %v, want %v", len(got), len(tests))
+		t.FailNow()
+	}
+
+	for i, test := range tests {
+		g := got[test.want.ID]
+		if g.Name != test.want.Name ||
+			g.Total != test.want.Total ||
+			g.Count != test.want.Count ||
+			!g.First.Equal(test.want.First) ||
+			!g.Last.Equal(test.want.Last) ||
+			math.Abs(g.Share-test.want.Share) > 1e-9 {
+			t.Logf("test %v failed: got %+v but wanted %+v", i, g, test.want)
+			t.FailNow()
+		}
+	}
+}
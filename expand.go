@@ -0,0 +1,153 @@
+package fplib
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// dayOccurrence is a single transaction occurrence that falls on one of the
+// days of a set of results.
+type dayOccurrence struct {
+	// The index of the day within the results.
+	day int
+	// The index of this occurrence among all of the occurrences of its
+	// transaction within the window.
+	occurrence int
+	amount     int
+}
+
+// newResultEntry returns the entry for a single occurrence of txi.
+func newResultEntry(txi *TX, o dayOccurrence) ResultEntry {
+	return ResultEntry{
+		ID:         txi.ID,
+		Name:       txi.Name,
+		Amount:     o.amount,
+		Occurrence: o.occurrence,
+		Account:    txi.Account,
+		Category:   txi.Category,
+		Tags:       txi.Tags,
+	}
+}
+
+// expandOccurrences expands every active transaction into the occurrences
+// that fall on the provided results' days. Transactions are expanded
+// concurrently by a bounded pool of workers, each writing into the slot of the
+// transaction it's expanding, so the output is the same regardless of the
+// order in which they finish. Inactive transactions have empty slots.
+//
+// If any transactions fail to expand, the error of the first one (in the order
+// they were provided) is returned. If ctx is done before every transaction has
+// been expanded, ctx.Err() is returned.
+func expandOccurrences(ctx context.Context, tx []TX, results []Result, startDate time.Time, endDate time.Time, progress *progressTracker) ([][]dayOccurrence, error) {
+	slots := make([][]dayOccurrence, len(tx))
+	errs := make([]error, len(tx))
+
+	active := []int{}
+
+	for i := range tx {
+		if tx[i].Active {
+			active = append(active, i)
+		}
+	}
+
+	progress.report(PhaseRecurrences, 0, len(active))
+
+	if len(active) == 0 {
+		return slots, nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan int)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	// the feeder stops handing out transactions as soon as ctx is done
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+
+		for _, i := range active {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for range min(runtime.GOMAXPROCS(0), len(active)) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				slots[i], errs[i] = expandTX(&tx[i], results, startDate, endDate)
+
+				select {
+				case done <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	// progress is reported from this goroutine alone, so reporters don't need
+	// to be safe for concurrent use
+	for n := 1; n <= len(active); n++ {
+		select {
+		case <-done:
+		case <-ctx.Done():
+			cancel()
+			wg.Wait()
+
+			return nil, ctx.Err()
+		}
+
+		progress.step(PhaseRecurrences, n, len(active))
+	}
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return slots, nil
+}
+
+// expandTX returns every occurrence of a single transaction that falls exactly
+// on one of the provided results' days.
+func expandTX(txi *TX, results []Result, startDate time.Time, endDate time.Time) ([]dayOccurrence, error) {
+	allOccurrences, err := getOccurrences(txi, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := make([]dayOccurrence, 0, len(allOccurrences))
+
+	for j, dt := range allOccurrences {
+		day, ok := getDayIndex(results, dt)
+		if !ok {
+			continue
+		}
+
+		occurrences = append(occurrences, dayOccurrence{
+			day:        day,
+			occurrence: j,
+			amount:     txi.GetAmountOn(dt),
+		})
+	}
+
+	return occurrences, nil
+}
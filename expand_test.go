@@ -0,0 +1,117 @@
+package fplib_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestGetResultsContext(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+	txs := getBenchmarkTXs(1500)
+
+	// expanding concurrently must not change the results or the order of
+	// entries within a day
+	{
+		want, err := fpl.GetResults(txs, start, end, 1000, nil)
+		if err != nil {
+			t.Logf("GetResults failed: %v", err.Error())
+			t.FailNow()
+		}
+
+		for i := range 3 {
+			got, err := fpl.GetResultsContext(context.Background(), txs, start, end, 1000, nil)
+			if err != nil {
+				t.Logf("run %v failed: %v", i, err.Error())
+				t.FailNow()
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Logf("run %v results differ", i)
+				t.FailNow()
+			}
+		}
+
+		positions := make(map[string]int)
+		for i := range txs {
+			positions[txs[i].ID] = i
+		}
+
+		for _, r := range want {
+			for j := 1; j < len(r.DayEntries); j++ {
+				if positions[r.DayEntries[j-1].ID] >= positions[r.DayEntries[j].ID] {
+					t.Logf("entries out of order on %v: %v then %v", r.Date, r.DayEntries[j-1].ID, r.DayEntries[j].ID)
+					t.FailNow()
+				}
+			}
+		}
+	}
+
+	// an already cancelled context
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		got, err := fpl.GetResultsContext(ctx, txs, start, end, 0, nil)
+		if !errors.Is(err, context.Canceled) || len(got) != 0 {
+			t.Logf("expected cancellation, got %v results and err %v", len(got), err)
+			t.FailNow()
+		}
+	}
+
+	// cancelled partway through the calculations
+	{
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		hook := func(status string) {
+			if strings.HasPrefix(status, "calculating") {
+				cancel()
+			}
+		}
+
+		got, err := fpl.GetResultsContext(ctx, txs, start, end, 0, hook)
+		if !errors.Is(err, context.Canceled) || len(got) != 0 {
+			t.Logf("expected cancellation, got %v results and err %v", len(got), err)
+			t.FailNow()
+		}
+	}
+
+	// an expired deadline
+	{
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		_, err := fpl.GetResultsContext(ctx, txs, start, end, 0, nil)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Logf("expected deadline exceeded, got %v", err)
+			t.FailNow()
+		}
+	}
+
+	// the error of the first invalid transaction is returned every time
+	{
+		invalid := append([]fpl.TX{}, txs[:100]...)
+		invalid[40].Name = "first"
+		invalid[40].RRule = "invalid"
+		invalid[80].Name = "second"
+		invalid[80].RRule = "invalid"
+
+		for i := range 3 {
+			_, err := fpl.GetResultsContext(context.Background(), invalid, start, end, 0, nil)
+			if err == nil || !strings.Contains(err.Error(), "first") {
+				t.Logf("run %v returned the wrong error: %v", i, err)
+				t.FailNow()
+			}
+		}
+	}
+}
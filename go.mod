@@ -5,4 +5,5 @@ go 1.22.5
 require (
 	github.com/charles-m-knox/go-uuid v0.0.2
 	github.com/teambition/rrule-go v1.8.2
+	gopkg.in/yaml.v3 v3.0.1
 )
@@ -0,0 +1,114 @@
+package fplib
+
+import (
+	"fmt"
+	"time"
+)
+
+// Goal is a savings target, such as "save $6,000 for a vacation by
+// 2027-06-01". A goal is reached when the balance is at or above Amount on or
+// before Target.
+type Goal struct {
+	Name   string    `yaml:"name"`
+	Amount int       `yaml:"amount"` // in cents; 500 = $5.00
+	Target time.Time `yaml:"target"`
+	// The ID of the Account that the goal is saved in, if any. Contributions
+	// generated by GetContributionTX are transferred into this account.
+	Account string `yaml:"account"`
+}
+
+// GoalStatus is the outcome of evaluating a Goal against a set of results.
+type GoalStatus struct {
+	// Whether the balance reached the goal's amount on or before its target.
+	Reached bool
+	// The first day that the balance reached the goal's amount, even if that
+	// is after the target. This is the zero time if it was never reached.
+	ReachedOn time.Time
+	// The balance on the target date, or on the last day of the results if
+	// the target is after them.
+	BalanceOnTarget int
+	// How far BalanceOnTarget is below the goal's amount; zero if reached.
+	Shortfall int
+	// The additional amount that would have to be contributed every month,
+	// starting on the first day of the results, to make up the shortfall by
+	// the target date; zero if reached.
+	MonthlyContribution int
+	// Whether the target is before the first day of the results, in which
+	// case there's no balance on the target to judge the goal by, so Reached
+	// is false and BalanceOnTarget, Shortfall and MonthlyContribution are all
+	// zero. ReachedOn is still set if the balance reaches the amount later.
+	TargetBeforeResults bool
+}
+
+// Evaluate determines whether the provided results reach the goal, and if not,
+// how much would need to be contributed monthly to reach it.
+func (g *Goal) Evaluate(results []Result) GoalStatus {
+	status := GoalStatus{}
+
+	if len(results) == 0 {
+		status.Shortfall = g.Amount
+
+		return status
+	}
+
+	for _, r := range results {
+		if !r.Date.After(g.Target) {
+			status.BalanceOnTarget = r.Balance
+		}
+
+		if status.ReachedOn.IsZero() && r.Balance >= g.Amount {
+			status.ReachedOn = r.Date
+		}
+	}
+
+	if g.Target.Before(results[0].Date) {
+		status.TargetBeforeResults = true
+
+		return status
+	}
+
+	status.Reached = !status.ReachedOn.IsZero() && !status.ReachedOn.After(g.Target)
+	if status.Reached {
+		return status
+	}
+
+	status.Shortfall = g.Amount - status.BalanceOnTarget
+
+	// count how many times a monthly contribution would occur between the
+	// first day of the results and the target date
+	contribution := g.GetContributionTX(results[0].Date, 0, "")
+
+	occurrences, err := getOccurrences(&contribution, results[0].Date, g.Target)
+	if err != nil {
+		return status
+	}
+
+	months := max(len(occurrences), 1)
+	// round up so that the goal is never missed by a few cents
+	status.MonthlyContribution = (status.Shortfall + months - 1) / months
+
+	return status
+}
+
+// GetContributionTX returns a new monthly transaction for the provided
+// contribution amount that starts on start and ends on the goal's target date.
+// If the goal has an Account, the contribution is a transfer into it from the
+// account with the ID from; otherwise, it is simply added to the balance.
+func (g *Goal) GetContributionTX(start time.Time, amount int, from string) TX {
+	tx := GetNewTX(start)
+	tx.Amount = amount
+	tx.Name = fmt.Sprintf("%v contribution", g.Name)
+	tx.StartsDay = start.Day()
+	tx.StartsMonth = int(start.Month())
+	tx.StartsYear = start.Year()
+	tx.EndsDay = g.Target.Day()
+	tx.EndsMonth = int(g.Target.Month())
+	tx.EndsYear = g.Target.Year()
+
+	if g.Account != "" {
+		tx.Account = from
+		tx.TransferTo = g.Account
+	}
+
+	return tx
+}
@@ -0,0 +1,127 @@
+package fplib_test
+
+import (
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestGoalEvaluate(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2027, time.December, 31, 0, 0, 0, 0, time.UTC)
+	target := time.Date(2027, time.June, 1, 0, 0, 0, 0, time.UTC)
+
+	// $100 of savings on the 1st of every month, for 30 months until the
+	// target date
+	savings := fpl.TX{
+		Amount:      10000,
+		Name:        "Savings",
+		Active:      true,
+		Frequency:   fpl.MONTHLY,
+		Interval:    1,
+		StartsDay:   1,
+		StartsMonth: 1,
+		StartsYear:  2025,
+	}
+
+	results, err := fpl.GetResults([]fpl.TX{savings}, start, end, 0, nil)
+	if err != nil {
+		t.Logf("GetResults failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	tests := []struct {
+		goal fpl.Goal
+		want fpl.GoalStatus
+	}{
+		{
+			fpl.Goal{Name: "Vacation", Amount: 200000, Target: target},
+			fpl.GoalStatus{true, time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC), 300000, 0, 0, false},
+		},
+		{
+			// reached, but too late
+			fpl.Goal{Name: "Vacation", Amount: 330000, Target: target},
+			fpl.GoalStatus{false, time.Date(2027, time.September, 1, 0, 0, 0, 0, time.UTC), 300000, 30000, 1000, false},
+		},
+		{
+			// never reached, and the shortfall doesn't divide evenly
+			fpl.Goal{Name: "Vacation", Amount: 1000000, Target: target},
+			fpl.GoalStatus{false, time.Time{}, 300000, 700000, 23334, false},
+		},
+		{
+			// there's no balance on a target before the results to judge by
+			fpl.Goal{Name: "Vacation", Amount: 200000, Target: start.AddDate(0, 0, -1)},
+			fpl.GoalStatus{false, time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC), 0, 0, 0, true},
+		},
+	}
+
+	for i, test := range tests {
+		got := test.goal.Evaluate(results)
+		if got.Reached != test.want.Reached ||
+			!got.ReachedOn.Equal(test.want.ReachedOn) ||
+			got.BalanceOnTarget != test.want.BalanceOnTarget ||
+			got.Shortfall != test.want.Shortfall ||
+			got.MonthlyContribution != test.want.MonthlyContribution ||
+			got.TargetBeforeResults != test.want.TargetBeforeResults {
+			t.Logf("test %v failed: got %+v but wanted %+v", i, got, test.want)
+			t.FailNow()
+		}
+
+		if got.Reached || got.TargetBeforeResults {
+			continue
+		}
+
+		// adding the suggested contribution must reach the goal
+		contribution := test.goal.GetContributionTX(start, got.MonthlyContribution, "")
+
+		again, err := fpl.GetResults([]fpl.TX{savings, contribution}, start, end, 0, nil)
+		if err != nil {
+			t.Logf("test %v GetResults failed: %v", i, err.Error())
+			t.FailNow()
+		}
+
+		if status := test.goal.Evaluate(again); !status.Reached {
+			t.Logf("test %v failed: contribution did not reach the goal: %+v", i, status)
+			t.FailNow()
+		}
+	}
+
+	// no results
+	{
+		g := fpl.Goal{Amount: 100}
+
+		got := g.Evaluate([]fpl.Result{})
+		if got.Reached || got.Shortfall != 100 {
+			t.Logf("empty results failed: got %+v", got)
+			t.FailNow()
+		}
+	}
+}
+
+func TestGoalGetContributionTX(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC)
+	g := fpl.Goal{Name: "Vacation", Target: time.Date(2027, time.June, 1, 0, 0, 0, 0, time.UTC), Account: "savings"}
+
+	got := g.GetContributionTX(start, 5000, "checking")
+
+	if got.Amount != 5000 || got.Name != "Vacation contribution" || got.Frequency != fpl.MONTHLY {
+		t.Logf("wrong contribution: %+v", got)
+		t.FailNow()
+	}
+
+	if got.GetStartDateString() != "2025-01-15" || got.GetEndsDateString() != "2027-06-01" {
+		t.Logf("wrong contribution dates: %v to %v", got.GetStartDateString(), got.GetEndsDateString())
+		t.FailNow()
+	}
+
+	if got.Account != "checking" || got.TransferTo != "savings" {
+		t.Logf("wrong contribution accounts: %v to %v", got.Account, got.TransferTo)
+		t.FailNow()
+	}
+}
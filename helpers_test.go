@@ -0,0 +1,28 @@
+package fplib_test
+
+import (
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// date returns midnight UTC on the provided day.
+func date(y, m, d int) time.Time {
+	return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+}
+
+// monthlyTX returns an active transaction that occurs on the provided day of
+// every month, starting in January 2025.
+func monthlyTX(id, name string, amount, day int) fpl.TX {
+	return fpl.TX{
+		ID:          id,
+		Amount:      amount,
+		Name:        name,
+		Active:      true,
+		Frequency:   fpl.MONTHLY,
+		Interval:    1,
+		StartsDay:   day,
+		StartsMonth: 1,
+		StartsYear:  2025,
+	}
+}
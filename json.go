@@ -0,0 +1,291 @@
+package fplib
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// txFileJSONSchema describes the JSON encoding of a TXFile.
+//
+//go:embed txfile.schema.json
+var txFileJSONSchema []byte
+
+// GetTXFileJSONSchema returns a JSON Schema document that describes the JSON
+// encoding of a TXFile, for validating transaction files in other languages
+// and editors.
+func GetTXFileJSONSchema() []byte {
+	return slices.Clone(txFileJSONSchema)
+}
+
+// WeekdayNames are the names used for TX.Weekdays in JSON, indexed by the
+// weekday's key in TX.Weekdays.
+var WeekdayNames = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// jsonDateLayout is the layout of dates in JSON.
+const jsonDateLayout = "2006-01-02"
+
+// jsonDate is a date that's encoded in JSON as "YYYY-MM-DD", or as null if
+// it's zero. Dates are decoded as midnight UTC.
+type jsonDate time.Time
+
+func (d jsonDate) MarshalJSON() ([]byte, error) {
+	t := time.Time(d)
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(t.Format(jsonDateLayout))
+}
+
+func (d *jsonDate) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*d = jsonDate{}
+
+		return nil
+	}
+
+	var s string
+
+	err := json.Unmarshal(b, &s)
+	if err != nil {
+		return err
+	}
+
+	t, err := time.Parse(jsonDateLayout, s)
+	if err != nil {
+		return fmt.Errorf("invalid date %q, expected YYYY-MM-DD", s)
+	}
+
+	*d = jsonDate(t)
+
+	return nil
+}
+
+// jsonWeekdays is TX.Weekdays encoded in JSON as the names of the active
+// weekdays, from Monday to Sunday. It's decoded with an entry for every
+// weekday, as in GetWeekdaysMap.
+type jsonWeekdays map[int]bool
+
+func (w jsonWeekdays) MarshalJSON() ([]byte, error) {
+	names := []string{}
+
+	for weekday, name := range WeekdayNames {
+		if w[weekday] {
+			names = append(names, name)
+		}
+	}
+
+	for weekday, active := range w {
+		if active && (weekday < 0 || weekday >= len(WeekdayNames)) {
+			return nil, fmt.Errorf("invalid weekday %v, expected 0 (Monday) to 6 (Sunday)", weekday)
+		}
+	}
+
+	return json.Marshal(names)
+}
+
+func (w *jsonWeekdays) UnmarshalJSON(b []byte) error {
+	var names []string
+
+	err := json.Unmarshal(b, &names)
+	if err != nil {
+		return err
+	}
+
+	// every weekday is present, as in GetWeekdaysMap, not just the active ones
+	weekdays := jsonWeekdays(GetWeekdaysMap())
+
+	for _, name := range names {
+		weekday := slices.Index(WeekdayNames, name)
+		if weekday < 0 {
+			return fmt.Errorf("invalid weekday %q, expected one of %v", name, WeekdayNames)
+		}
+
+		weekdays[weekday] = true
+	}
+
+	*w = weekdays
+
+	return nil
+}
+
+// MarshalJSON encodes the transaction with its weekdays as names, such as
+// ["monday", "friday"].
+func (tx TX) MarshalJSON() ([]byte, error) {
+	type plain TX
+
+	return json.Marshal(struct {
+		plain
+		Weekdays jsonWeekdays `json:"weekdays"`
+	}{plain(tx), jsonWeekdays(tx.Weekdays)})
+}
+
+// UnmarshalJSON decodes a transaction encoded by MarshalJSON. Weekdays always
+// has an entry for every weekday, even if weekdays is omitted.
+func (tx *TX) UnmarshalJSON(b []byte) error {
+	type plain TX
+
+	if tx.Weekdays == nil {
+		tx.Weekdays = GetWeekdaysMap()
+	}
+
+	v := struct {
+		*plain
+		Weekdays jsonWeekdays `json:"weekdays"`
+	}{(*plain)(tx), jsonWeekdays(tx.Weekdays)}
+
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+
+	tx.Weekdays = v.Weekdays
+
+	return nil
+}
+
+// MarshalJSON encodes the scheduled amount with its effective date as
+// "YYYY-MM-DD".
+func (s ScheduledAmount) MarshalJSON() ([]byte, error) {
+	type plain ScheduledAmount
+
+	return json.Marshal(struct {
+		plain
+		Effective jsonDate `json:"effective"`
+	}{plain(s), jsonDate(s.Effective)})
+}
+
+// UnmarshalJSON decodes a scheduled amount encoded by MarshalJSON.
+func (s *ScheduledAmount) UnmarshalJSON(b []byte) error {
+	type plain ScheduledAmount
+
+	v := struct {
+		*plain
+		Effective jsonDate `json:"effective"`
+	}{(*plain)(s), jsonDate(s.Effective)}
+
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+
+	s.Effective = time.Time(v.Effective)
+
+	return nil
+}
+
+// MarshalJSON encodes the result with its date as "YYYY-MM-DD".
+func (r Result) MarshalJSON() ([]byte, error) {
+	type plain Result
+
+	return json.Marshal(struct {
+		plain
+		Date jsonDate `json:"date"`
+	}{plain(r), jsonDate(r.Date)})
+}
+
+// UnmarshalJSON decodes a result encoded by MarshalJSON.
+func (r *Result) UnmarshalJSON(b []byte) error {
+	type plain Result
+
+	v := struct {
+		*plain
+		Date jsonDate `json:"date"`
+	}{(*plain)(r), jsonDate(r.Date)}
+
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+
+	r.Date = time.Time(v.Date)
+
+	return nil
+}
+
+// MarshalJSON encodes the stats with their dates as "YYYY-MM-DD", or null if
+// they're unset.
+func (s TXStats) MarshalJSON() ([]byte, error) {
+	type plain TXStats
+
+	return json.Marshal(struct {
+		plain
+		MinBalanceDate        jsonDate `json:"minBalanceDate"`
+		MaxBalanceDate        jsonDate `json:"maxBalanceDate"`
+		LongestBelowZeroStart jsonDate `json:"longestBelowZeroStart"`
+		LargestDayOutflowDate jsonDate `json:"largestDayOutflowDate"`
+	}{
+		plain(s),
+		jsonDate(s.MinBalanceDate),
+		jsonDate(s.MaxBalanceDate),
+		jsonDate(s.LongestBelowZeroStart),
+		jsonDate(s.LargestDayOutflowDate),
+	})
+}
+
+// UnmarshalJSON decodes stats encoded by MarshalJSON.
+func (s *TXStats) UnmarshalJSON(b []byte) error {
+	type plain TXStats
+
+	v := struct {
+		*plain
+		MinBalanceDate        jsonDate `json:"minBalanceDate"`
+		MaxBalanceDate        jsonDate `json:"maxBalanceDate"`
+		LongestBelowZeroStart jsonDate `json:"longestBelowZeroStart"`
+		LargestDayOutflowDate jsonDate `json:"largestDayOutflowDate"`
+	}{
+		(*plain)(s),
+		jsonDate(s.MinBalanceDate),
+		jsonDate(s.MaxBalanceDate),
+		jsonDate(s.LongestBelowZeroStart),
+		jsonDate(s.LargestDayOutflowDate),
+	}
+
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+
+	s.MinBalanceDate = time.Time(v.MinBalanceDate)
+	s.MaxBalanceDate = time.Time(v.MaxBalanceDate)
+	s.LongestBelowZeroStart = time.Time(v.LongestBelowZeroStart)
+	s.LargestDayOutflowDate = time.Time(v.LargestDayOutflowDate)
+
+	return nil
+}
+
+// MarshalJSON encodes the settings with their dates as "YYYY-MM-DD", or null
+// if they're unset.
+func (s TXFileSettings) MarshalJSON() ([]byte, error) {
+	type plain TXFileSettings
+
+	return json.Marshal(struct {
+		plain
+		StartDate jsonDate `json:"startDate"`
+		EndDate   jsonDate `json:"endDate"`
+	}{plain(s), jsonDate(s.StartDate), jsonDate(s.EndDate)})
+}
+
+// UnmarshalJSON decodes settings encoded by MarshalJSON.
+func (s *TXFileSettings) UnmarshalJSON(b []byte) error {
+	type plain TXFileSettings
+
+	v := struct {
+		*plain
+		StartDate jsonDate `json:"startDate"`
+		EndDate   jsonDate `json:"endDate"`
+	}{(*plain)(s), jsonDate(s.StartDate), jsonDate(s.EndDate)}
+
+	err := json.Unmarshal(b, &v)
+	if err != nil {
+		return err
+	}
+
+	s.StartDate = time.Time(v.StartDate)
+	s.EndDate = time.Time(v.EndDate)
+
+	return nil
+}
@@ -0,0 +1,257 @@
+package fplib_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+// getFullTX returns a transaction with every field set.
+func getFullTX() fpl.TX {
+	tx := fpl.GetNewTX(time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC))
+	tx.Weekdays[0] = true
+	tx.Weekdays[4] = true
+	tx.Note = "note"
+	tx.RRule = "DTSTART:20250103T000000Z\nRRULE:FREQ=DAILY;INTERVAL=3"
+	tx.Selected = true
+	tx.AmountSchedule = []fpl.ScheduledAmount{{Effective: time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC), Amount: -500}}
+	tx.Distribution = &fpl.Distribution{Type: fpl.EMPIRICAL, Min: 1, Max: 2, StdDev: 3, Samples: []int{4}}
+	tx.Account = "checking"
+	tx.TransferTo = "savings"
+	tx.Category = "Savings"
+	tx.Tags = []string{"a", "b"}
+
+	return tx
+}
+
+//nolint:cyclop
+func TestTXJSON(t *testing.T) {
+	t.Parallel()
+
+	tx := getFullTX()
+
+	b, err := json.Marshal(tx)
+	if err != nil {
+		t.Logf("failed to marshal: %v", err.Error())
+		t.FailNow()
+	}
+
+	for _, want := range []string{`"weekdays":["monday","friday"]`, `"effective":"2025-06-01"`, `"amount":500,`, `"createdAt":"2025-01-01T00:00:00Z"`} {
+		if !strings.Contains(string(b), want) {
+			t.Logf("expected %v in %v", want, string(b))
+			t.Fail()
+		}
+	}
+
+	var got fpl.TX
+
+	err = json.Unmarshal(b, &got)
+	if err != nil {
+		t.Logf("failed to unmarshal: %v", err.Error())
+		t.FailNow()
+	}
+
+	// every weekday survives the round trip, including the inactive ones
+	if !reflect.DeepEqual(got, tx) {
+		t.Logf("round trip failed: got %+v but wanted %+v", got, tx)
+		t.FailNow()
+	}
+
+	// a missing weekdays key still decodes to every weekday
+	var minimal fpl.TX
+
+	err = json.Unmarshal([]byte(`{"name":"minimal"}`), &minimal)
+	if err != nil || !reflect.DeepEqual(minimal.Weekdays, fpl.GetWeekdaysMap()) {
+		t.Logf("unexpected weekdays without a weekdays key: %v (%v)", minimal.Weekdays, err)
+		t.FailNow()
+	}
+
+	// optional fields are left out when unset
+	b, err = json.Marshal(fpl.TX{Name: "minimal"})
+	if err != nil || strings.Contains(string(b), "tags") || !strings.Contains(string(b), `"weekdays":[]`) {
+		t.Logf("unexpected minimal encoding: %v (%v)", string(b), err)
+		t.FailNow()
+	}
+
+	invalid := []string{
+		`{"weekdays":["someday"]}`,
+		`{"weekdays":"monday"}`,
+		`{"amountSchedule":[{"effective":"06/01/2025","amount":5}]}`,
+	}
+
+	for i, input := range invalid {
+		if err := json.Unmarshal([]byte(input), &got); err == nil {
+			t.Logf("invalid input %v was accepted", i)
+			t.Fail()
+		}
+	}
+
+	if _, err := json.Marshal(fpl.TX{Weekdays: map[int]bool{7: true}}); err == nil {
+		t.Logf("expected an error for an invalid weekday")
+		t.Fail()
+	}
+}
+
+func TestResultJSON(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+	txs := getBenchmarkTXs(20)
+	txs[0].Tags = []string{"tag"}
+
+	results, err := fpl.GetResults(txs, start, end, 1000, nil)
+	if err != nil {
+		t.Logf("GetResults failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	b, err := json.Marshal(results)
+	if err != nil {
+		t.Logf("failed to marshal results: %v", err.Error())
+		t.FailNow()
+	}
+
+	if !strings.HasPrefix(string(b), `[{"record":0,"balance":1000,`) || !strings.Contains(string(b), `"date":"2025-01-01"`) {
+		t.Logf("unexpected encoding: %v", string(b)[:100])
+		t.FailNow()
+	}
+
+	var got []fpl.Result
+
+	err = json.Unmarshal(b, &got)
+	if err != nil || !reflect.DeepEqual(got, results) {
+		t.Logf("results round trip failed: %v", err)
+		t.FailNow()
+	}
+
+	stats := fpl.CalculateStats(results)
+
+	b, err = json.Marshal(stats)
+	if err != nil || !strings.Contains(string(b), `"minBalanceDate":"`) {
+		t.Logf("unexpected stats encoding: %v (%v)", string(b), err)
+		t.FailNow()
+	}
+
+	var gotStats fpl.TXStats
+
+	err = json.Unmarshal(b, &gotStats)
+	if err != nil || gotStats != stats {
+		t.Logf("stats round trip failed: got %+v but wanted %+v (%v)", gotStats, stats, err)
+		t.FailNow()
+	}
+
+	// unset dates are null
+	b, err = json.Marshal(fpl.TXStats{})
+	if err != nil || !strings.Contains(string(b), `"minBalanceDate":null`) {
+		t.Logf("unexpected empty stats encoding: %v (%v)", string(b), err)
+		t.FailNow()
+	}
+}
+
+// getSchemaProperties returns the sorted property names of a schema object.
+func getSchemaProperties(schema map[string]any) []string {
+	properties, _ := schema["properties"].(map[string]any)
+	keys := []string{}
+
+	for key := range properties {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// getJSONKeys returns the sorted keys of an encoded JSON object.
+func getJSONKeys(v any) []string {
+	object, _ := v.(map[string]any)
+	keys := []string{}
+
+	for key := range object {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
+// TestTXFileJSONSchema ensures that the schema describes exactly the keys
+// that are encoded, so the two can't drift apart.
+func TestTXFileJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	var schema map[string]any
+
+	err := json.Unmarshal(fpl.GetTXFileJSONSchema(), &schema)
+	if err != nil {
+		t.Logf("the schema isn't valid JSON: %v", err.Error())
+		t.FailNow()
+	}
+
+	defs, _ := schema["$defs"].(map[string]any)
+	def := func(name string) map[string]any {
+		d, _ := defs[name].(map[string]any)
+
+		return d
+	}
+
+	f := fpl.TXFile{
+		Version: fpl.TXFileVersion,
+		Settings: fpl.TXFileSettings{
+			StartBalance: 1,
+			StartDate:    time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:      time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC),
+		},
+		TXs: []fpl.TX{getFullTX()},
+	}
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		t.Logf("failed to marshal the file: %v", err.Error())
+		t.FailNow()
+	}
+
+	var encoded map[string]any
+
+	err = json.Unmarshal(b, &encoded)
+	if err != nil {
+		t.Logf("failed to unmarshal the file: %v", err.Error())
+		t.FailNow()
+	}
+
+	tx, _ := encoded["transactions"].([]any)[0].(map[string]any)
+
+	tests := []struct {
+		name    string
+		schema  map[string]any
+		encoded any
+	}{
+		{"file", schema, encoded},
+		{"settings", def("settings"), encoded["settings"]},
+		{"transaction", def("transaction"), tx},
+		{"scheduledAmount", def("scheduledAmount"), tx["amountSchedule"].([]any)[0]},
+		{"distribution", def("distribution"), tx["distribution"]},
+	}
+
+	for _, test := range tests {
+		want, got := getSchemaProperties(test.schema), getJSONKeys(test.encoded)
+		if !slices.Equal(got, want) {
+			t.Logf("%v: encoded keys %v don't match the schema's %v", test.name, got, want)
+			t.Fail()
+		}
+	}
+
+	// the returned schema can't be modified
+	fpl.GetTXFileJSONSchema()[0] = 'x'
+	if fpl.GetTXFileJSONSchema()[0] != '{' {
+		t.Logf("the schema was modified")
+		t.Fail()
+	}
+}
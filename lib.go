@@ -1,10 +1,11 @@
 package fplib
 
 import (
+	"context"
 	"encoding/csv"
 	"errors"
 	"fmt"
-	"sort"
+	"math"
 	"strings"
 	"time"
 
@@ -29,34 +30,62 @@ import (
 
 type TX struct { // transaction
 	// Order  int    `yaml:"order"`  // manual ordering
-	Amount int    `yaml:"amount"` // in cents; 500 = $5.00
-	Active bool   `yaml:"active"`
-	Name   string `yaml:"name"`
-	Note   string `yaml:"note"`
+	Amount int    `yaml:"amount" json:"amount"` // in cents; 500 = $5.00
+	Active bool   `yaml:"active" json:"active"`
+	Name   string `yaml:"name" json:"name"`
+	Note   string `yaml:"note" json:"note"`
 	// for examples of rrules:
 	// https://github.com/teambition/rrule-go/blob/f71921a2b0a18e6e73c74dea155f3a549d71006d/rrule.go#L91
 	// https://github.com/teambition/rrule-go/blob/master/rruleset_test.go
 	// https://labix.org/python-dateutil/#head-88ab2bc809145fcf75c074817911575616ce7caf
-	RRule string `yaml:"rrule"`
+	RRule string `yaml:"rrule" json:"rrule"`
 
 	// for when users don't want to use the rrules:
 
 	// The frequency of recurrence, such as MONTHLY/YEARLY/DAILY.
-	Frequency string `yaml:"frequency"`
+	Frequency string `yaml:"frequency" json:"frequency"`
 	// The interval of recurrence. A value of 1 means that this occurs every
 	// 1 month/year/day. A value of 6 means that this occurs every 6th month/year/day.
-	Interval    int          `yaml:"interval"`
-	Weekdays    map[int]bool `yaml:"weekdays"` // monday starts on 0
-	StartsDay   int          `yaml:"startsDay"`
-	StartsMonth int          `yaml:"startsMonth"`
-	StartsYear  int          `yaml:"startsYear"`
-	EndsDay     int          `yaml:"endsDay"`
-	EndsMonth   int          `yaml:"endsMonth"`
-	EndsYear    int          `yaml:"endsYear"`
-	ID          string       `yaml:"id"`
-	CreatedAt   time.Time    `yaml:"createdAt"`
-	UpdatedAt   time.Time    `yaml:"updatedAt"`
-	Selected    bool         `yaml:"selected"` // when activated in the transactions table
+	Interval    int          `yaml:"interval" json:"interval"`
+	Weekdays    map[int]bool `yaml:"weekdays" json:"weekdays"` // monday starts on 0
+	StartsDay   int          `yaml:"startsDay" json:"startsDay"`
+	StartsMonth int          `yaml:"startsMonth" json:"startsMonth"`
+	StartsYear  int          `yaml:"startsYear" json:"startsYear"`
+	EndsDay     int          `yaml:"endsDay" json:"endsDay"`
+	EndsMonth   int          `yaml:"endsMonth" json:"endsMonth"`
+	EndsYear    int          `yaml:"endsYear" json:"endsYear"`
+	ID          string       `yaml:"id" json:"id"`
+	CreatedAt   time.Time    `yaml:"createdAt" json:"createdAt"`
+	UpdatedAt   time.Time    `yaml:"updatedAt" json:"updatedAt"`
+	Selected    bool         `yaml:"selected" json:"selected"` // when activated in the transactions table
+	// AmountSchedule optionally changes Amount from a given date onwards,
+	// such as when a promotional rate ends or a raise takes effect, so that
+	// one transaction can be kept across price changes. See GetAmountOn.
+	AmountSchedule []ScheduledAmount `yaml:"amountSchedule" json:"amountSchedule,omitempty"`
+	// Distribution optionally describes how the amount of each occurrence
+	// varies, such as for groceries or utilities. It is only consulted by
+	// Simulate; GetResults always uses the point value from GetAmountOn.
+	Distribution *Distribution `yaml:"distribution" json:"distribution,omitempty"`
+	// Account is the ID of the Account that this transaction belongs to. An
+	// empty value refers to the first account passed to GetAccountResults.
+	Account string `yaml:"account" json:"account,omitempty"`
+	// TransferTo is the ID of the Account that receives this transaction.
+	// When set, this transaction is a transfer: the absolute value of its
+	// amount is debited from Account and credited to TransferTo.
+	TransferTo string `yaml:"transferTo" json:"transferTo,omitempty"`
+	// Category is a single grouping for reporting purposes, such as
+	// "Subscriptions". See CalculateCategoryStats.
+	Category string `yaml:"category" json:"category,omitempty"`
+	// Tags are free-form labels for reporting purposes. See
+	// CalculateTagStats.
+	Tags []string `yaml:"tags" json:"tags,omitempty"`
+}
+
+// ScheduledAmount is an amount that takes effect on a specific date as part of
+// a transaction's AmountSchedule.
+type ScheduledAmount struct {
+	Effective time.Time `yaml:"effective" json:"effective"`
+	Amount    int       `yaml:"amount" json:"amount"` // in cents; 500 = $5.00
 }
 
 type PreCalculatedResult struct {
@@ -65,22 +94,39 @@ type PreCalculatedResult struct {
 	DayTransactionAmounts []int
 }
 
+// ResultEntry is a single transaction occurrence within a Result.
+type ResultEntry struct {
+	// The ID of the transaction definition that this entry is from.
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Amount int    `json:"amount"`
+	// The zero-based index of this occurrence among all of the occurrences
+	// of its transaction definition within the results.
+	Occurrence int `json:"occurrence"`
+	// The ID of the Account that this entry applies to, if any. For the
+	// receiving side of a transfer, this is the receiving account.
+	Account  string   `json:"account"`
+	Category string   `json:"category"`
+	Tags     []string `json:"tags"`
+}
+
 // A result is a csv/table output row as shown in a results page.
 type Result struct {
-	Record                   int
-	Date                     time.Time
-	Balance                  int
-	CumulativeIncome         int
-	CumulativeExpenses       int
-	DayExpenses              int
-	DayIncome                int
-	DayNet                   int
-	DayTransactionNames      string
-	DiffFromStart            int
-	DayTransactionNamesSlice []string
-	ID                       string
-	CreatedAt                string
-	UpdatedAt                string
+	Record                   int           `json:"record"`
+	Date                     time.Time     `json:"date"`
+	Balance                  int           `json:"balance"`
+	CumulativeIncome         int           `json:"cumulativeIncome"`
+	CumulativeExpenses       int           `json:"cumulativeExpenses"`
+	DayExpenses              int           `json:"dayExpenses"`
+	DayIncome                int           `json:"dayIncome"`
+	DayNet                   int           `json:"dayNet"`
+	DayTransactionNames      string        `json:"dayTransactionNames"`
+	DiffFromStart            int           `json:"diffFromStart"`
+	DayTransactionNamesSlice []string      `json:"dayTransactionNamesSlice"`
+	DayEntries               []ResultEntry `json:"dayEntries"`
+	ID                       string        `json:"id"`
+	CreatedAt                string        `json:"createdAt"`
+	UpdatedAt                string        `json:"updatedAt"`
 }
 
 // GetNewTX returns an empty transaction with sensible defaults based on the
@@ -111,6 +157,26 @@ func GetNewTX(t time.Time) TX {
 	}
 }
 
+// GetAmountOn returns the amount of the transaction for an occurrence on the
+// provided date. The most recent AmountSchedule entry that is effective on or
+// before dt wins; if there is none, the transaction's Amount is returned.
+func (tx *TX) GetAmountOn(dt time.Time) int {
+	amount := tx.Amount
+
+	var effective time.Time
+
+	for _, sa := range tx.AmountSchedule {
+		if sa.Effective.After(dt) || sa.Effective.Before(effective) {
+			continue
+		}
+
+		amount = sa.Amount
+		effective = sa.Effective
+	}
+
+	return amount
+}
+
 // GetWeekdaysMap returns a map that can be used like this:
 //
 // m := GetWeekdaysMap()
@@ -212,245 +278,377 @@ func (tx *TX) GetWeekdaysCheckedMap(checked, unchecked string) map[int]string {
 // 	return returnValue
 // }
 
+// GetResults calculates a result for every day from startDate to endDate,
+// inclusive, from the occurrences of every active transaction. It's the same
+// as GetResultsContext without the ability to cancel it.
 func GetResults(tx []TX, startDate time.Time, endDate time.Time, startBalance int, statusHook func(status string)) ([]Result, error) {
+	return GetResultsContext(context.Background(), tx, startDate, endDate, startBalance, statusHook)
+}
+
+// GetResultsContext is the same as GetResultsWithOptions, except that progress
+// is reported to statusHook as human-readable statuses every
+// DefaultProgressInterval items. statusHook may be nil.
+func GetResultsContext(ctx context.Context, tx []TX, startDate time.Time, endDate time.Time, startBalance int, statusHook func(status string)) ([]Result, error) {
+	return GetResultsWithOptions(ctx, tx, startDate, endDate, ResultsOptions{
+		StartBalance: startBalance,
+		Progress:     StatusHookReporter(statusHook),
+	})
+}
+
+// ResultsOptions controls how GetResultsWithOptions calculates results.
+type ResultsOptions struct {
+	StartBalance int
+	// Receives progress events while the results are calculated. May be nil.
+	Progress ProgressReporter
+	// Within a phase, progress is reported every ProgressInterval items, as
+	// well as when the phase starts and completes. Values below 1 mean
+	// DefaultProgressInterval.
+	ProgressInterval int
+}
+
+// GetResultsWithOptions calculates a result for every day from startDate to
+// endDate, inclusive, from the occurrences of every active transaction.
+// Transactions are expanded into their occurrences concurrently, but the
+// results are always the same as if they were expanded one at a time.
+//
+// If ctx is cancelled or its deadline passes before the results are ready,
+// ctx.Err() is returned along with no results.
+func GetResultsWithOptions(ctx context.Context, tx []TX, startDate time.Time, endDate time.Time, opts ResultsOptions) ([]Result, error) {
+	progress := newProgressTracker(opts.Progress, opts.ProgressInterval)
+
+	results, err := getResults(ctx, tx, startDate, endDate, opts.StartBalance, progress)
+	if err != nil {
+		return []Result{}, err
+	}
+
+	progress.report(PhaseDone, len(results), len(results))
+
+	return results, nil
+}
+
+// getResults calculates results as described by GetResultsWithOptions. Every
+// phase except PhaseDone is reported to progress, so that calculations made up
+// of several projections can share a single progressTracker.
+func getResults(ctx context.Context, tx []TX, startDate time.Time, endDate time.Time, startBalance int, progress *progressTracker) ([]Result, error) {
 	if startDate.After(endDate) {
 		return []Result{}, fmt.Errorf("start date is after end date: %v vs %v", startDate, endDate)
 	}
 
-	// start by quickly generating an index of every single date from startDate to endDate
-	dates := make(map[int64]Result)
-	preCalculatedDates := make(map[int64]PreCalculatedResult)
+	if err := ctx.Err(); err != nil {
+		return []Result{}, err
+	}
 
-	r, err := rrule.NewRRule(
-		rrule.ROption{
-			Freq:    rrule.DAILY,
-			Dtstart: startDate,
-			Until:   endDate,
-		},
-	)
+	// start by quickly generating every single date from startDate to endDate;
+	// since they're already in order, no sorting is needed later on
+	progress.report(PhasePreparing, 0, 0)
+
+	results := getResultDays(startDate, endDate)
+	resultsLen := len(results)
+
+	slots, err := expandOccurrences(ctx, tx, results, startDate, endDate, progress)
 	if err != nil {
-		return []Result{}, fmt.Errorf("failed to construct rrule for results date window: %v", err.Error())
+		return []Result{}, err
 	}
 
-	allDates := r.All()
+	// offsets[d+1] starts out as the number of occurrences on day d, and is
+	// then turned into the position of day d+1's first entry
+	offsets := make([]int, resultsLen+1)
+	total := 0
 
-	statusHook("preparing dates...")
-
-	for i, dt := range allDates {
-		dtInt := dt.Unix()
-		dates[dtInt] = Result{
-			Record: i,
-			Date:   dt,
+	for _, occurrences := range slots {
+		for _, o := range occurrences {
+			offsets[o.day+1]++
 		}
-		preCalculatedDates[dtInt] = PreCalculatedResult{
-			Date: dt,
+
+		total += len(occurrences)
+	}
+
+	for d := 1; d <= resultsLen; d++ {
+		offsets[d] += offsets[d-1]
+	}
+
+	// every entry shares a single backing slice, grouped by day, and
+	// occurrences were collected in TX order so each day's entries are too
+	entries := make([]ResultEntry, total)
+	next := make([]int, resultsLen)
+	copy(next, offsets)
+
+	for i, occurrences := range slots {
+		for _, o := range occurrences {
+			entries[next[o.day]] = newResultEntry(&tx[i], o)
+			next[o.day]++
 		}
 	}
 
-	emptyDate := time.Date(0, time.Month(0), 0, 0, 0, 0, 0, time.UTC)
+	totals := runningTotals{balance: startBalance}
 
-	// iterate over every TX definition, starting with its start date
-	txLen := len(tx)
+	for i := range results {
+		if i%progress.interval == 0 {
+			// to avoid unnecessary slowdown, only update every so often
+			progress.report(PhaseCalculating, i, resultsLen)
 
-	statusHook(fmt.Sprintf("recurrences... [%v/%v]", 0, txLen))
+			if err := ctx.Err(); err != nil {
+				return []Result{}, err
+			}
+		}
 
-	for i, txi := range tx {
-		if !txi.Active {
-			continue
+		var dayEntries []ResultEntry
+		if offsets[i] < offsets[i+1] {
+			// the capacity is capped so that appending to one day's entries
+			// can't overwrite the next day's
+			dayEntries = entries[offsets[i]:offsets[i+1]:offsets[i+1]]
 		}
 
-		if i%1000 == 0 {
-			// to avoid unnecessary slowdown, only update every 1000 iterations
-			statusHook(fmt.Sprintf("recurrences... [%v/%v]", i+1, txLen))
+		totals.addDay(&results[i], dayEntries)
+	}
+
+	return results, nil
+}
+
+// secondsPerDay is the length of a day without any daylight saving time
+// transition.
+const secondsPerDay = 24 * 60 * 60
+
+// getResultDays returns an empty result for every day from startDate to
+// endDate, inclusive, at the same time of day as startDate. These are the same
+// days that a daily recurrence from startDate until endDate would produce.
+func getResultDays(startDate time.Time, endDate time.Time) []Result {
+	first := startDate.Truncate(time.Second)
+
+	results := make([]Result, countResultDays(startDate, endDate))
+	for i := range results {
+		results[i] = Result{
+			Record: i,
+			Date:   first.AddDate(0, 0, i),
 		}
+	}
 
-		var allOccurrences []time.Time
+	return results
+}
 
-		if txi.RRule != "" {
-			s, err := rrule.StrToRRuleSet(txi.RRule)
-			if err != nil {
-				return []Result{}, fmt.Errorf(
-					"failed to process rrule for tx %v: %v",
-					txi.Name,
-					err.Error(),
-				)
-			}
+// countResultDays returns the number of results that getResultDays returns
+// for the same window, without allocating them.
+func countResultDays(startDate time.Time, endDate time.Time) int {
+	first := startDate.Truncate(time.Second)
+	last := endDate.Truncate(time.Second)
+
+	// estimate the number of days, then correct for the time of day and any
+	// daylight saving time transitions
+	n := int((last.Unix()-first.Unix())/secondsPerDay) + 1
+	for n > 0 && first.AddDate(0, 0, n-1).After(last) {
+		n--
+	}
 
-			allOccurrences = s.Between(
-				startDate,
-				endDate,
-				true,
-			)
-		} else {
-			txiStartsDate := time.Date(txi.StartsYear, time.Month(txi.StartsMonth), txi.StartsDay, 0, 0, 0, 0, time.UTC)
-			txiEndsDate := time.Date(txi.EndsYear, time.Month(txi.EndsMonth), txi.EndsDay, 0, 0, 0, 0, time.UTC)
-			// input validation: if the end date for the transaction definition is after
-			// the final end date, then just use the ending date.
-			// also, if the transaction definition's end date is unset (equal to emptyDate),
-			// then default to the ending date as well
-			if txiEndsDate.After(endDate) || txiEndsDate == emptyDate {
-				txiEndsDate = endDate
-			}
-			// input validation: if the transaction definition's start date is
-			// unset (equal to emptyDate), then default to the start date
-			if txiStartsDate == emptyDate {
-				txiStartsDate = startDate
-			}
+	for !first.AddDate(0, 0, n).After(last) {
+		n++
+	}
 
-			// This is the rrule that determines the recurrence pattern for
-			// this particular transaction definition.
-			var s *rrule.RRule
-			// These are the rrule options that we are construction for this
-			// particular transaction definition.
-			var rr rrule.ROption
-
-			// convert the user-input weekdays into a value that rrule lib will
-			// accept
-			weekdays := []rrule.Weekday{}
-
-			for wi, active := range txi.Weekdays {
-				if !active {
-					continue
-				}
-
-				switch wi {
-				case rrule.MO.Day():
-					weekdays = append(weekdays, rrule.MO)
-				case rrule.TU.Day():
-					weekdays = append(weekdays, rrule.TU)
-				case rrule.WE.Day():
-					weekdays = append(weekdays, rrule.WE)
-				case rrule.TH.Day():
-					weekdays = append(weekdays, rrule.TH)
-				case rrule.FR.Day():
-					weekdays = append(weekdays, rrule.FR)
-				case rrule.SA.Day():
-					weekdays = append(weekdays, rrule.SA)
-				case rrule.SU.Day():
-					weekdays = append(weekdays, rrule.SU)
-				default:
-					break
-				}
-			}
+	return n
+}
 
-			rr.Dtstart = txiStartsDate
-			rr.Until = txiEndsDate
-			rr.Interval = txi.Interval
-
-			// TODO: this code is unable to support weekdays when using
-			// yearly/monthly recurrence patterns. This library needs to
-			// increment to the next version and the UIs need to be updated
-			// to support this capability.
-
-			switch txi.Frequency {
-			case rrule.YEARLY.String():
-				rr.Freq = rrule.YEARLY
-			case rrule.MONTHLY.String():
-				rr.Freq = rrule.MONTHLY
-			default:
-				rr.Freq = rrule.DAILY
-				rr.Byweekday = weekdays
-			}
+// getDayIndex returns the index of the result whose date is exactly dt. Since
+// results are one calendar day apart, the index can be derived from the time
+// elapsed since the first result, rounded to the nearest day to allow for
+// daylight saving time transitions. Occurrences at a different time of day than
+// the results don't belong to any of them.
+func getDayIndex(results []Result, dt time.Time) (int, bool) {
+	if len(results) == 0 {
+		return 0, false
+	}
 
-			s, err = rrule.NewRRule(rr)
-			if err != nil {
-				return []Result{}, fmt.Errorf(
-					"failed to construct rrule for tx %v: %v",
-					txi.Name,
-					err.Error(),
-				)
-			}
+	elapsed := dt.Unix() - results[0].Date.Unix()
+	if elapsed < 0 {
+		return 0, false
+	}
+
+	i := int((elapsed + secondsPerDay/2) / secondsPerDay)
+	if i >= len(results) || results[i].Date.Unix() != dt.Unix() {
+		return 0, false
+	}
+
+	return i, true
+}
+
+// runningTotals holds the values that carry over from one day of results to
+// the next.
+type runningTotals struct {
+	balance            int
+	diff               int
+	cumulativeIncome   int
+	cumulativeExpenses int
+}
+
+// addDay populates a single day's result from the transaction occurrences on
+// that day, and updates the running totals. Every projection builds its
+// results through this function so that they all share the same semantics.
+func (t *runningTotals) addDay(r *Result, entries []ResultEntry) {
+	if len(entries) > 0 {
+		r.DayTransactionNamesSlice = make([]string, len(entries))
+	}
+
+	var names strings.Builder
 
-			allOccurrences = s.Between(startDate, endDate, true)
+	for i, e := range entries {
+		// names are joined with "; ", except that nothing is added before a
+		// name while the joined names are still empty
+		if names.Len() > 0 {
+			names.WriteString("; ")
 		}
 
-		for _, dt := range allOccurrences {
-			dtInt := dt.Unix()
-			newResult := preCalculatedDates[dtInt]
-			newResult.Date = dt
-			newResult.DayTransactionAmounts = append(newResult.DayTransactionAmounts, txi.Amount)
-			newResult.DayTransactionNames = append(newResult.DayTransactionNames, txi.Name)
-			preCalculatedDates[dtInt] = newResult
+		names.WriteString(e.Name)
+		r.DayTransactionNamesSlice[i] = e.Name
+
+		// determine if the amount is an expense or income
+		amt := e.Amount
+		if amt >= 0 {
+			r.DayIncome += amt
+			t.cumulativeIncome += amt
+		} else {
+			r.DayExpenses += amt
+			t.cumulativeExpenses += amt
 		}
-	}
 
-	results := []Result{}
-	for _, result := range dates {
-		results = append(results, result)
+		r.DayNet += amt
+		t.diff += amt
+		t.balance += amt
 	}
 
-	resultsLen := len(results)
-	statusHook(fmt.Sprintf("sorting dates... [%v]", resultsLen))
-	sort.SliceStable(
-		results,
-		func(i, j int) bool {
-			return results[j].Date.After(results[i].Date)
-		},
-	)
+	r.DayTransactionNames = names.String()
+	r.DayEntries = entries
+	r.Balance = t.balance
+	r.CumulativeIncome = t.cumulativeIncome
+	r.CumulativeExpenses = t.cumulativeExpenses
+	r.DiffFromStart = t.diff
+}
 
-	// now that it's sorted, we can roll out the calculations
-	currentBalance := startBalance
-	diff := 0
-	cumulativeIncome := 0
-	cumulativeExpenses := 0
+// carryDay updates the running totals with a day whose own values (DayNet,
+// DayIncome and DayExpenses) have already been calculated by addDay, and sets
+// that day's running values from them.
+func (t *runningTotals) carryDay(r *Result) {
+	t.balance += r.DayNet
+	t.diff += r.DayNet
+	t.cumulativeIncome += r.DayIncome
+	t.cumulativeExpenses += r.DayExpenses
+
+	r.Balance = t.balance
+	r.CumulativeIncome = t.cumulativeIncome
+	r.CumulativeExpenses = t.cumulativeExpenses
+	r.DiffFromStart = t.diff
+}
 
-	statusHook(fmt.Sprintf("calculating... [%v/%v]", 0, resultsLen))
+// getOccurrences expands the recurrence pattern of a single transaction
+// definition into every occurrence between startDate and endDate, inclusive.
+func getOccurrences(txi *TX, startDate time.Time, endDate time.Time) ([]time.Time, error) {
+	s, err := getRecurrence(txi, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
 
-	for i := range results {
-		if i%1000 == 0 {
-			// to avoid unnecessary slowdown, only update every 1000 iterations
-			statusHook(fmt.Sprintf("calculating... [%v/%v]", i+1, resultsLen))
-		}
+	return s.Between(startDate, endDate, true), nil
+}
 
-		resultsDateInt := results[i].Date.Unix()
-		numDayTransactionAmounts := len(preCalculatedDates[resultsDateInt].DayTransactionAmounts)
-		numDdayTransactionNames := len(preCalculatedDates[resultsDateInt].DayTransactionNames)
+// getRecurrence builds the recurrence pattern of a single transaction
+// definition, either from its RRule or from its frequency, interval, weekdays
+// and start/end dates. startDate and endDate are used when the transaction's
+// own start/end dates are unset.
+func getRecurrence(txi *TX, startDate time.Time, endDate time.Time) (*rrule.Set, error) {
+	emptyDate := time.Date(0, time.Month(0), 0, 0, 0, 0, 0, time.UTC)
 
-		// if for some reason not all transaction names and amounts match up,
-		// exit now
-		if numDayTransactionAmounts != numDdayTransactionNames {
-			return results, fmt.Errorf(
-				"there was a different number of transaction amounts versus transaction names for date %v",
-				resultsDateInt,
+	if txi.RRule != "" {
+		s, err := rrule.StrToRRuleSet(txi.RRule)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"failed to process rrule for tx %v: %v",
+				txi.Name,
+				err.Error(),
 			)
 		}
 
-		for j := range preCalculatedDates[resultsDateInt].DayTransactionAmounts {
-			// determine if the amount is an expense or income
-			amt := preCalculatedDates[resultsDateInt].DayTransactionAmounts[j]
-			if amt >= 0 {
-				results[i].DayIncome += amt
-				cumulativeIncome += amt
-			} else {
-				results[i].DayExpenses += amt
-				cumulativeExpenses += amt
-			}
+		return s, nil
+	}
 
-			// basically just doing a join on a slice of strings, should
-			// use the proper method for this in the future
-			name := preCalculatedDates[resultsDateInt].DayTransactionNames[j]
-			if results[i].DayTransactionNames == "" {
-				results[i].DayTransactionNames = name
-			} else {
-				results[i].DayTransactionNames += fmt.Sprintf("; %v", name)
-			}
+	txiStartsDate := time.Date(txi.StartsYear, time.Month(txi.StartsMonth), txi.StartsDay, 0, 0, 0, 0, time.UTC)
+	txiEndsDate := time.Date(txi.EndsYear, time.Month(txi.EndsMonth), txi.EndsDay, 0, 0, 0, 0, time.UTC)
+	// input validation: if the end date for the transaction definition is after
+	// the final end date, then just use the ending date.
+	// also, if the transaction definition's end date is unset (equal to emptyDate),
+	// then default to the ending date as well
+	if txiEndsDate.After(endDate) || txiEndsDate == emptyDate {
+		txiEndsDate = endDate
+	}
+	// input validation: if the transaction definition's start date is
+	// unset (equal to emptyDate), then default to the start date
+	if txiStartsDate == emptyDate {
+		txiStartsDate = startDate
+	}
 
-			results[i].DayTransactionNamesSlice = append(results[i].DayTransactionNamesSlice, name)
+	// These are the rrule options that we are construction for this
+	// particular transaction definition.
+	var rr rrule.ROption
 
-			results[i].DayNet += amt
-			diff += amt
-			currentBalance += amt
+	// convert the user-input weekdays into a value that rrule lib will
+	// accept
+	weekdays := []rrule.Weekday{}
+
+	for wi, active := range txi.Weekdays {
+		if !active {
+			continue
 		}
 
-		results[i].Balance = currentBalance
-		results[i].CumulativeIncome = cumulativeIncome
-		results[i].CumulativeExpenses = cumulativeExpenses
-		results[i].DiffFromStart = diff
+		switch wi {
+		case rrule.MO.Day():
+			weekdays = append(weekdays, rrule.MO)
+		case rrule.TU.Day():
+			weekdays = append(weekdays, rrule.TU)
+		case rrule.WE.Day():
+			weekdays = append(weekdays, rrule.WE)
+		case rrule.TH.Day():
+			weekdays = append(weekdays, rrule.TH)
+		case rrule.FR.Day():
+			weekdays = append(weekdays, rrule.FR)
+		case rrule.SA.Day():
+			weekdays = append(weekdays, rrule.SA)
+		case rrule.SU.Day():
+			weekdays = append(weekdays, rrule.SU)
+		default:
+			break
+		}
 	}
 
-	statusHook(fmt.Sprintf("done [%v/%v]", resultsLen, resultsLen))
+	rr.Dtstart = txiStartsDate
+	rr.Until = txiEndsDate
+	rr.Interval = txi.Interval
+
+	// TODO: this code is unable to support weekdays when using
+	// yearly/monthly recurrence patterns. This library needs to
+	// increment to the next version and the UIs need to be updated
+	// to support this capability.
+
+	switch txi.Frequency {
+	case rrule.YEARLY.String():
+		rr.Freq = rrule.YEARLY
+	case rrule.MONTHLY.String():
+		rr.Freq = rrule.MONTHLY
+	default:
+		rr.Freq = rrule.DAILY
+		rr.Byweekday = weekdays
+	}
 
-	return results, nil
+	// This is the rrule that determines the recurrence pattern for
+	// this particular transaction definition.
+	s, err := rrule.NewRRule(rr)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to construct rrule for tx %v: %v",
+			txi.Name,
+			err.Error(),
+		)
+	}
+
+	set := &rrule.Set{}
+	set.RRule(s)
+
+	return set, nil
 }
 
 // GetStartDateString returns a formatted date string for the transaction's
@@ -520,23 +718,46 @@ func GetDateFromStrSafe(s string, t time.Time) time.Time {
 }
 
 type TXStats struct {
-	DailySpending   int
-	DailyIncome     int
-	DailyNet        int
-	MonthlySpending int
-	MonthlyIncome   int
-	MonthlyNet      int
-	YearlySpending  int
-	YearlyIncome    int
-	YearlyNet       int
+	DailySpending   int `json:"dailySpending"`
+	DailyIncome     int `json:"dailyIncome"`
+	DailyNet        int `json:"dailyNet"`
+	MonthlySpending int `json:"monthlySpending"`
+	MonthlyIncome   int `json:"monthlyIncome"`
+	MonthlyNet      int `json:"monthlyNet"`
+	YearlySpending  int `json:"yearlySpending"`
+	YearlyIncome    int `json:"yearlyIncome"`
+	YearlyNet       int `json:"yearlyNet"`
+
+	// The lowest and highest balances, and the first day each occurred.
+	MinBalance     int       `json:"minBalance"`
+	MinBalanceDate time.Time `json:"minBalanceDate"`
+	MaxBalance     int       `json:"maxBalance"`
+	MaxBalanceDate time.Time `json:"maxBalanceDate"`
+	// The mean of every day's balance, rounded to the nearest cent.
+	AverageBalance int `json:"averageBalance"`
+	// The longest run of consecutive days with a balance below zero, and the
+	// first day of that run.
+	LongestBelowZeroDays  int       `json:"longestBelowZeroDays"`
+	LongestBelowZeroStart time.Time `json:"longestBelowZeroStart"`
+	// The largest total expenses on a single day (a negative value), and the
+	// first day it occurred.
+	LargestDayOutflow     int       `json:"largestDayOutflow"`
+	LargestDayOutflowDate time.Time `json:"largestDayOutflowDate"`
+	// The number of days with at least one transaction.
+	ActiveDays int `json:"activeDays"`
 }
 
 func CalculateStats(results []Result) TXStats {
 	count := len(results)
-	if count <= 1 {
+	if count == 0 {
 		return TXStats{}
 	}
 
+	stats := calculateBalanceStats(results)
+	if count <= 1 {
+		return stats
+	}
+
 	ci := count - 1
 
 	// // Cumulative expenses at the end of the calculation period.
@@ -573,17 +794,123 @@ func CalculateStats(results []Result) TXStats {
 	ys = CalculateYearlyRate(cuex, count)
 	yi = CalculateYearlyRate(cuin, count)
 
-	return TXStats{
-		DailySpending:   ds,
-		DailyIncome:     di,
-		DailyNet:        ds + di,
-		MonthlySpending: ms,
-		MonthlyIncome:   mi,
-		MonthlyNet:      ms + mi,
-		YearlySpending:  ys,
-		YearlyIncome:    yi,
-		YearlyNet:       ys + yi,
+	stats.DailySpending = ds
+	stats.DailyIncome = di
+	stats.DailyNet = ds + di
+	stats.MonthlySpending = ms
+	stats.MonthlyIncome = mi
+	stats.MonthlyNet = ms + mi
+	stats.YearlySpending = ys
+	stats.YearlyIncome = yi
+	stats.YearlyNet = ys + yi
+
+	return stats
+}
+
+// CalculateStatsWithConvention is like CalculateStats, but the monthly and
+// yearly rates are calculated from the dates of the first and last results
+// using the provided day count convention (see CountMonths), such as Calendar
+// for rates based on the actual calendar months and years that are spanned.
+// The daily rates are unaffected.
+func CalculateStatsWithConvention(results []Result, convention string) (TXStats, error) {
+	stats := CalculateStats(results)
+
+	if convention == Actual36525 {
+		return stats, nil
 	}
+
+	count := len(results)
+
+	var first, last time.Time
+
+	if count > 0 {
+		first = results[0].Date
+		last = results[count-1].Date
+	}
+
+	months, err := CountMonths(first, last, convention)
+	if err != nil {
+		return TXStats{}, err
+	}
+
+	years, err := CountYears(first, last, convention)
+	if err != nil {
+		return TXStats{}, err
+	}
+
+	if count <= 1 {
+		return stats, nil
+	}
+
+	cuex := results[count-1].CumulativeExpenses
+	cuin := results[count-1].CumulativeIncome
+
+	stats.MonthlySpending = CalculateRate(cuex, months)
+	stats.MonthlyIncome = CalculateRate(cuin, months)
+	stats.MonthlyNet = stats.MonthlySpending + stats.MonthlyIncome
+	stats.YearlySpending = CalculateRate(cuex, years)
+	stats.YearlyIncome = CalculateRate(cuin, years)
+	stats.YearlyNet = stats.YearlySpending + stats.YearlyIncome
+
+	return stats, nil
+}
+
+// calculateBalanceStats populates the fields of TXStats that are derived
+// from each day's balance and activity, in a single pass over the results.
+func calculateBalanceStats(results []Result) TXStats {
+	stats := TXStats{
+		MinBalance:     results[0].Balance,
+		MinBalanceDate: results[0].Date,
+		MaxBalance:     results[0].Balance,
+		MaxBalanceDate: results[0].Date,
+	}
+
+	total := 0
+	belowZero := 0
+
+	var belowZeroStart time.Time
+
+	for _, r := range results {
+		total += r.Balance
+
+		if r.Balance < stats.MinBalance {
+			stats.MinBalance = r.Balance
+			stats.MinBalanceDate = r.Date
+		}
+
+		if r.Balance > stats.MaxBalance {
+			stats.MaxBalance = r.Balance
+			stats.MaxBalanceDate = r.Date
+		}
+
+		if r.Balance < 0 {
+			if belowZero == 0 {
+				belowZeroStart = r.Date
+			}
+
+			belowZero++
+
+			if belowZero > stats.LongestBelowZeroDays {
+				stats.LongestBelowZeroDays = belowZero
+				stats.LongestBelowZeroStart = belowZeroStart
+			}
+		} else {
+			belowZero = 0
+		}
+
+		if r.DayExpenses < stats.LargestDayOutflow {
+			stats.LargestDayOutflow = r.DayExpenses
+			stats.LargestDayOutflowDate = r.Date
+		}
+
+		if len(r.DayTransactionNamesSlice) > 0 {
+			stats.ActiveDays++
+		}
+	}
+
+	stats.AverageBalance = int(math.Round(float64(total) / float64(len(results))))
+
+	return stats
 }
 
 func (s *TXStats) GetStats() string {
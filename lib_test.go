@@ -297,6 +297,37 @@ func TestGetResults(t *testing.T) {
 			false,
 			days,
 		},
+		{
+			// This test case is the same as the first test case, but the
+			// amount doubles at the start of 2025 via an amount schedule.
+			[]fpl.TX{
+				{
+					Amount:      tx1Amount,
+					Name:        tx1,
+					Active:      true,
+					Frequency:   fpl.MONTHLY,
+					Interval:    1,
+					StartsDay:   1,
+					StartsMonth: 1,
+					StartsYear:  2024,
+					ID:          uuid.New(),
+					AmountSchedule: []fpl.ScheduledAmount{
+						{Effective: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), Amount: 2 * tx1Amount},
+					},
+				},
+			},
+			start,
+			end,
+			startBalance,
+			[]fpl.Result{{
+				Balance:            startBalance + 12*tx1Amount + 14*2*tx1Amount,
+				DiffFromStart:      12*tx1Amount + 14*2*tx1Amount,
+				CumulativeExpenses: 12*tx1Amount + 14*2*tx1Amount,
+				CumulativeIncome:   0,
+			}},
+			false,
+			days,
+		},
 	}
 
 	for i, test := range tests {
@@ -356,6 +387,38 @@ func TestGetResults(t *testing.T) {
 	}
 }
 
+func TestGetAmountOn(t *testing.T) {
+	t.Parallel()
+
+	tx := fpl.TX{
+		Amount: -1000,
+		AmountSchedule: []fpl.ScheduledAmount{
+			// intentionally out of order
+			{Effective: date(2025, 6, 1), Amount: -3000},
+			{Effective: date(2025, 1, 1), Amount: -2000},
+		},
+	}
+
+	tests := []struct {
+		dt   time.Time
+		want int
+	}{
+		{date(2024, 12, 31), -1000},
+		{date(2025, 1, 1), -2000},
+		{date(2025, 5, 31), -2000},
+		{date(2025, 6, 1), -3000},
+		{date(2030, 1, 1), -3000},
+	}
+
+	for i, test := range tests {
+		got := tx.GetAmountOn(test.dt)
+		if got != test.want {
+			t.Logf("test %v failed: got %v but wanted %v", i, got, test.want)
+			t.FailNow()
+		}
+	}
+}
+
 //nolint:cyclop
 func TestGetNewTX(t *testing.T) {
 	t.Parallel()
@@ -622,6 +685,109 @@ Yearly net: $0.00`,
 	}
 }
 
+//nolint:cyclop
+func TestCalculateStats(t *testing.T) {
+	t.Parallel()
+
+	results := []fpl.Result{
+		{Date: date(2025, 1, 1), Balance: 1000},
+		{Date: date(2025, 1, 2), Balance: -500, DayExpenses: -1500, DayTransactionNamesSlice: []string{"Rent"}},
+		{Date: date(2025, 1, 3), Balance: -700, DayExpenses: -200, DayTransactionNamesSlice: []string{"Gas"}},
+		{Date: date(2025, 1, 4), Balance: 2300, DayIncome: 3000, DayTransactionNamesSlice: []string{"Paycheck"}},
+		{Date: date(2025, 1, 5), Balance: -1700, DayExpenses: -4000, DayTransactionNamesSlice: []string{"Car", "Gas"}},
+		{Date: date(2025, 1, 6), Balance: 2300},
+	}
+
+	got := fpl.CalculateStats(results)
+
+	tests := []struct {
+		name      string
+		got, want any
+	}{
+		{"MinBalance", got.MinBalance, -1700},
+		{"MinBalanceDate", got.MinBalanceDate, date(2025, 1, 5)},
+		{"MaxBalance", got.MaxBalance, 2300},
+		{"MaxBalanceDate", got.MaxBalanceDate, date(2025, 1, 4)},
+		{"AverageBalance", got.AverageBalance, 450},
+		{"LongestBelowZeroDays", got.LongestBelowZeroDays, 2},
+		{"LongestBelowZeroStart", got.LongestBelowZeroStart, date(2025, 1, 2)},
+		{"LargestDayOutflow", got.LargestDayOutflow, -4000},
+		{"LargestDayOutflowDate", got.LargestDayOutflowDate, date(2025, 1, 5)},
+		{"ActiveDays", got.ActiveDays, 4},
+	}
+
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Logf("%v mismatch: got %v, want %v", test.name, test.got, test.want)
+			t.Fail()
+		}
+	}
+
+	// a single result still has balance statistics, but no rates
+	single := fpl.CalculateStats(results[1:2])
+	if single.MinBalance != -500 || single.LongestBelowZeroDays != 1 || single.DailyNet != 0 {
+		t.Logf("single result mismatch: got %+v", single)
+		t.Fail()
+	}
+}
+
+func TestCalculateStatsWithConvention(t *testing.T) {
+	t.Parallel()
+
+	// a single annual bill in a window that covers February only
+	start := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC)
+
+	txs := []fpl.TX{{
+		Amount:      -120000,
+		Name:        "Insurance",
+		Active:      true,
+		Frequency:   fpl.YEARLY,
+		Interval:    1,
+		StartsDay:   1,
+		StartsMonth: 2,
+		StartsYear:  2025,
+	}}
+
+	results, err := fpl.GetResults(txs, start, end, 0, nil)
+	if err != nil {
+		t.Logf("GetResults failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	tests := []struct {
+		convention              string
+		wantMonthly, wantYearly int
+		wantDaily               int
+		err                     bool
+	}{
+		{fpl.Actual36525, fpl.CalculateMonthlyRate(-120000, 28), fpl.CalculateYearlyRate(-120000, 28), -4286, false},
+		{fpl.Calendar, -120000, -1564286, -4286, false},
+		{fpl.Thirty360, -120000, -1440000, -4286, false},
+		{"nonsense", 0, 0, 0, true},
+	}
+
+	for i, test := range tests {
+		got, err := fpl.CalculateStatsWithConvention(results, test.convention)
+		if (err != nil) != test.err {
+			t.Logf("test %v failed: got error %v", i, err)
+			t.FailNow()
+		}
+
+		if got.MonthlySpending != test.wantMonthly || got.YearlySpending != test.wantYearly || got.DailySpending != test.wantDaily {
+			t.Logf("test %v failed: got %v/%v/%v but wanted %v/%v/%v", i,
+				got.DailySpending, got.MonthlySpending, got.YearlySpending,
+				test.wantDaily, test.wantMonthly, test.wantYearly)
+			t.FailNow()
+		}
+	}
+
+	if _, err := fpl.CalculateStatsWithConvention([]fpl.Result{}, fpl.Calendar); err != nil {
+		t.Logf("empty results threw error: %v", err.Error())
+		t.FailNow()
+	}
+}
+
 func TestGetResultsCSVString(t *testing.T) {
 	t.Parallel()
 
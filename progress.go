@@ -0,0 +1,116 @@
+package fplib
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProgressEvent describes how far along a calculation is.
+type ProgressEvent struct {
+	// One of PhasePreparing, PhaseRecurrences, PhaseCalculating, PhaseDone,
+	// PhaseScenarios or PhaseAccounts.
+	Phase string
+	// The number of items completed so far in this phase, out of Total. For
+	// PhaseRecurrences the items are active transactions, and for
+	// PhaseCalculating they're days. Calculations that project several sets
+	// of results report PhaseScenarios or PhaseAccounts before each one,
+	// where the items are the projections, followed by that projection's
+	// own phases. PhaseDone is only reported once, at the very end, with the
+	// same items as the last phase before it.
+	Done  int
+	Total int
+	// The time since the calculation started.
+	Elapsed time.Duration
+}
+
+// ProgressReporter receives progress events while results are calculated.
+// Events are always delivered from a single goroutine, one at a time.
+type ProgressReporter interface {
+	Progress(e ProgressEvent)
+}
+
+// ProgressFunc adapts an ordinary function into a ProgressReporter.
+type ProgressFunc func(e ProgressEvent)
+
+// Progress calls f(e).
+func (f ProgressFunc) Progress(e ProgressEvent) {
+	f(e)
+}
+
+// StatusHookReporter adapts a status hook, as accepted by GetResults, into a
+// ProgressReporter that passes it a human-readable status for every event,
+// such as "recurrences... [1000/2500]". A nil statusHook results in a nil
+// ProgressReporter, which reports nothing.
+func StatusHookReporter(statusHook func(status string)) ProgressReporter {
+	if statusHook == nil {
+		return nil
+	}
+
+	return ProgressFunc(func(e ProgressEvent) {
+		statusHook(e.String())
+	})
+}
+
+// String returns a human-readable status for the event.
+func (e ProgressEvent) String() string {
+	switch e.Phase {
+	case PhasePreparing:
+		return "preparing dates..."
+	case PhaseRecurrences:
+		return fmt.Sprintf("recurrences... [%v/%v]", e.Done, e.Total)
+	case PhaseCalculating:
+		return fmt.Sprintf("calculating... [%v/%v]", e.Done, e.Total)
+	case PhaseDone:
+		return fmt.Sprintf("done [%v/%v]", e.Done, e.Total)
+	case PhaseScenarios:
+		return fmt.Sprintf("scenarios... [%v/%v]", e.Done, e.Total)
+	case PhaseAccounts:
+		return fmt.Sprintf("accounts... [%v/%v]", e.Done, e.Total)
+	default:
+		return fmt.Sprintf("%v... [%v/%v]", e.Phase, e.Done, e.Total)
+	}
+}
+
+// progressTracker delivers progress events to an optional ProgressReporter.
+type progressTracker struct {
+	reporter ProgressReporter
+	// Events within a phase are only reported every interval items.
+	interval int
+	started  time.Time
+}
+
+// newProgressTracker returns a progressTracker that starts timing now. A nil
+// reporter is allowed, and an interval below 1 means DefaultProgressInterval.
+func newProgressTracker(reporter ProgressReporter, interval int) *progressTracker {
+	if interval < 1 {
+		interval = DefaultProgressInterval
+	}
+
+	return &progressTracker{
+		reporter: reporter,
+		interval: interval,
+		started:  time.Now(),
+	}
+}
+
+// report delivers an event unconditionally.
+func (p *progressTracker) report(phase string, done int, total int) {
+	if p.reporter == nil {
+		return
+	}
+
+	p.reporter.Progress(ProgressEvent{
+		Phase:   phase,
+		Done:    done,
+		Total:   total,
+		Elapsed: time.Since(p.started),
+	})
+}
+
+// step delivers an event if done is a multiple of the interval, or if the
+// phase is complete, so that frequent updates don't slow the calculation down.
+func (p *progressTracker) step(phase string, done int, total int) {
+	if done%p.interval == 0 || done == total {
+		p.report(phase, done, total)
+	}
+}
@@ -0,0 +1,260 @@
+package fplib_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestGetResultsWithOptions(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+	txs := getBenchmarkTXs(250)
+	txs[0].Active = false
+
+	events := []fpl.ProgressEvent{}
+	opts := fpl.ResultsOptions{
+		StartBalance: 500,
+		Progress: fpl.ProgressFunc(func(e fpl.ProgressEvent) {
+			events = append(events, e)
+		}),
+		ProgressInterval: 100,
+	}
+
+	got, err := fpl.GetResultsWithOptions(context.Background(), txs, start, end, opts)
+	if err != nil {
+		t.Logf("GetResultsWithOptions failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	want, err := fpl.GetResults(txs, start, end, 500, nil)
+	if err != nil {
+		t.Logf("GetResults with a nil status hook failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Logf("results differ from GetResults")
+		t.FailNow()
+	}
+
+	wantEvents := []fpl.ProgressEvent{
+		{Phase: fpl.PhasePreparing},
+		{Phase: fpl.PhaseRecurrences, Done: 0, Total: 249},
+		{Phase: fpl.PhaseRecurrences, Done: 100, Total: 249},
+		{Phase: fpl.PhaseRecurrences, Done: 200, Total: 249},
+		{Phase: fpl.PhaseRecurrences, Done: 249, Total: 249},
+		{Phase: fpl.PhaseCalculating, Done: 0, Total: 365},
+		{Phase: fpl.PhaseCalculating, Done: 100, Total: 365},
+		{Phase: fpl.PhaseCalculating, Done: 200, Total: 365},
+		{Phase: fpl.PhaseCalculating, Done: 300, Total: 365},
+		{Phase: fpl.PhaseDone, Done: 365, Total: 365},
+	}
+
+	if len(events) != len(wantEvents) {
+		t.Logf("wrong number of events: got %+v", events)
+		t.FailNow()
+	}
+
+	for i := range events {
+		if events[i].Phase != wantEvents[i].Phase || events[i].Done != wantEvents[i].Done || events[i].Total != wantEvents[i].Total {
+			t.Logf("event %v: got %+v but wanted %+v", i, events[i], wantEvents[i])
+			t.Fail()
+		}
+
+		if i > 0 && events[i].Elapsed < events[i-1].Elapsed {
+			t.Logf("event %v: elapsed time went backwards", i)
+			t.Fail()
+		}
+	}
+
+	// no reporter at all
+	opts.Progress = nil
+
+	_, err = fpl.GetResultsWithOptions(context.Background(), txs, start, end, opts)
+	if err != nil {
+		t.Logf("GetResultsWithOptions without a reporter failed: %v", err.Error())
+		t.FailNow()
+	}
+}
+
+func TestStatusHookReporter(t *testing.T) {
+	t.Parallel()
+
+	if fpl.StatusHookReporter(nil) != nil {
+		t.Logf("a nil status hook should result in a nil reporter")
+		t.FailNow()
+	}
+
+	tests := []struct {
+		event fpl.ProgressEvent
+		want  string
+	}{
+		{fpl.ProgressEvent{Phase: fpl.PhasePreparing}, "preparing dates..."},
+		{fpl.ProgressEvent{Phase: fpl.PhaseRecurrences, Done: 1000, Total: 2500}, "recurrences... [1000/2500]"},
+		{fpl.ProgressEvent{Phase: fpl.PhaseCalculating, Done: 0, Total: 365}, "calculating... [0/365]"},
+		{fpl.ProgressEvent{Phase: fpl.PhaseDone, Done: 365, Total: 365}, "done [365/365]"},
+		{fpl.ProgressEvent{Phase: fpl.PhaseScenarios, Done: 1, Total: 2}, "scenarios... [1/2]"},
+		{fpl.ProgressEvent{Phase: fpl.PhaseAccounts, Done: 0, Total: 3}, "accounts... [0/3]"},
+	}
+
+	for i, test := range tests {
+		got := ""
+		fpl.StatusHookReporter(func(status string) { got = status }).Progress(test.event)
+
+		if got != test.want {
+			t.Logf("test %v failed: got %v but wanted %v", i, got, test.want)
+			t.Fail()
+		}
+	}
+}
+
+func TestNilStatusHooks(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)
+	txs := getBenchmarkTXs(10)
+
+	if err := fpl.StreamResults(txs, start, end, 0, nil, func(_ fpl.Result) bool { return true }); err != nil {
+		t.Logf("StreamResults failed: %v", err.Error())
+		t.Fail()
+	}
+
+	if _, err := fpl.Simulate(txs, start, end, 0, 2, 1, nil, nil); err != nil {
+		t.Logf("Simulate failed: %v", err.Error())
+		t.Fail()
+	}
+
+	if _, err := fpl.CompareScenarios([][]fpl.TX{txs, txs[1:]}, start, end, 0, nil); err != nil {
+		t.Logf("CompareScenarios failed: %v", err.Error())
+		t.Fail()
+	}
+
+	if _, err := fpl.GetAccountResults(txs, []fpl.Account{{ID: "checking"}}, start, end, nil); err != nil {
+		t.Logf("GetAccountResults failed: %v", err.Error())
+		t.Fail()
+	}
+
+	if _, err := fpl.SolveMaxPurchase(txs, end, start, end, 100000000, 0, nil); err != nil {
+		t.Logf("SolveMaxPurchase failed: %v", err.Error())
+		t.Fail()
+	}
+}
+
+// TestProgressOptions ensures that every long-running calculation reports
+// typed events at the requested interval and can be cancelled.
+//
+//nolint:cyclop
+func TestProgressOptions(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+	txs := getBenchmarkTXs(120)
+	accounts := []fpl.Account{{ID: "checking"}, {ID: "card", Type: fpl.CreditCard, StatementClosingDay: 1}}
+	candidate := fpl.GetNewTX(end)
+
+	calculations := []struct {
+		name string
+		// the phase that's reported before each projection, if any
+		projection string
+		run        func(ctx context.Context, opts fpl.ResultsOptions) error
+	}{
+		{"Simulate", "", func(ctx context.Context, opts fpl.ResultsOptions) error {
+			_, err := fpl.SimulateWithOptions(ctx, txs, start, end, 5, 1, nil, opts)
+
+			return err
+		}},
+		{"StreamResults", "", func(ctx context.Context, opts fpl.ResultsOptions) error {
+			return fpl.StreamResultsWithOptions(ctx, txs, start, end, opts, func(_ fpl.Result) bool { return true })
+		}},
+		{"CompareScenarios", fpl.PhaseScenarios, func(ctx context.Context, opts fpl.ResultsOptions) error {
+			_, err := fpl.CompareScenariosWithOptions(ctx, [][]fpl.TX{txs, txs[1:]}, start, end, opts)
+
+			return err
+		}},
+		{"GetAccountResults", fpl.PhaseAccounts, func(ctx context.Context, opts fpl.ResultsOptions) error {
+			_, err := fpl.GetAccountResultsWithOptions(ctx, txs, accounts, start, end, opts)
+
+			return err
+		}},
+		{"SolveAffordability", "", func(ctx context.Context, opts fpl.ResultsOptions) error {
+			_, err := fpl.SolveAffordabilityWithOptions(ctx, txs, candidate, start, end, 0, opts)
+
+			return err
+		}},
+		{"GetRunway", "", func(ctx context.Context, opts fpl.ResultsOptions) error {
+			_, err := fpl.GetRunwayWithOptions(ctx, txs, start, end, 0, end, opts)
+
+			return err
+		}},
+	}
+
+	for _, c := range calculations {
+		events := []fpl.ProgressEvent{}
+		opts := fpl.ResultsOptions{
+			StartBalance: 100000000,
+			Progress: fpl.ProgressFunc(func(e fpl.ProgressEvent) {
+				events = append(events, e)
+			}),
+			ProgressInterval: 50,
+		}
+
+		err := c.run(context.Background(), opts)
+		if err != nil {
+			t.Logf("%v failed: %v", c.name, err.Error())
+			t.FailNow()
+		}
+
+		last := events[len(events)-1]
+		if last.Phase != fpl.PhaseDone || last.Done != last.Total {
+			t.Logf("%v: the last event was %+v, wanted PhaseDone", c.name, last)
+			t.Fail()
+		}
+
+		projections := 0
+
+		for i, e := range events {
+			if i < len(events)-1 && e.Phase == fpl.PhaseDone {
+				t.Logf("%v: PhaseDone was reported before the end", c.name)
+				t.Fail()
+			}
+
+			if (e.Phase == fpl.PhaseRecurrences || e.Phase == fpl.PhaseCalculating) && e.Done%50 != 0 && e.Done != e.Total {
+				t.Logf("%v: event %+v doesn't follow the interval", c.name, e)
+				t.Fail()
+			}
+
+			if e.Phase == c.projection {
+				projections++
+			}
+
+			if i > 0 && e.Elapsed < events[i-1].Elapsed {
+				t.Logf("%v: elapsed time went backwards", c.name)
+				t.Fail()
+			}
+		}
+
+		if c.projection != "" && projections != last.Total {
+			t.Logf("%v: got %v %v events, wanted %v", c.name, projections, c.projection, last.Total)
+			t.Fail()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err = c.run(ctx, opts)
+		if !errors.Is(err, context.Canceled) {
+			t.Logf("%v: expected context.Canceled, got %v", c.name, err)
+			t.Fail()
+		}
+	}
+}
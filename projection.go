@@ -0,0 +1,270 @@
+package fplib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrTXNotFound is returned when a Projection doesn't contain a transaction
+// with the requested ID.
+var ErrTXNotFound = errors.New("transaction not found in projection")
+
+// Projection holds the results of a set of transactions over a window, and
+// keeps them up to date as transactions are added, updated or removed. Each
+// change only recalculates the days that the changed transaction occurs on, and
+// the running totals from the first of those days onwards, instead of every
+// transaction and every day like GetResults does. This makes it well suited to
+// editors that recalculate after every keystroke.
+//
+// A Projection is not safe for concurrent use.
+type Projection struct {
+	startDate    time.Time
+	endDate      time.Time
+	startBalance int
+	// Every transaction, in order. Removed transactions leave their slot
+	// behind, so that the position of every other transaction stays the same.
+	slots   []projectionSlot
+	results []Result
+	// The slot of every entry in results[d].DayEntries, in ascending order.
+	daySlots [][]int
+	// Built on demand by queries, and discarded whenever the results change.
+	index *balanceIndex
+}
+
+// projectionSlot is a single transaction within a Projection, along with its
+// cached occurrences.
+type projectionSlot struct {
+	tx          TX
+	removed     bool
+	occurrences []dayOccurrence
+}
+
+// NewProjection calculates the results of the provided transactions from
+// startDate to endDate, inclusive, exactly as GetResults would, and returns a
+// Projection that can keep them up to date. The transactions are copied, so
+// later changes to tx don't affect the Projection.
+func NewProjection(tx []TX, startDate time.Time, endDate time.Time, startBalance int) (*Projection, error) {
+	if startDate.After(endDate) {
+		return nil, fmt.Errorf("start date is after end date: %v vs %v", startDate, endDate)
+	}
+
+	results := getResultDays(startDate, endDate)
+
+	expansions, err := expandOccurrences(context.Background(), tx, results, startDate, endDate, newProgressTracker(nil, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Projection{
+		startDate:    startDate,
+		endDate:      endDate,
+		startBalance: startBalance,
+		slots:        make([]projectionSlot, len(tx)),
+		results:      results,
+		daySlots:     make([][]int, len(results)),
+	}
+
+	for s := range tx {
+		p.slots[s] = projectionSlot{tx: tx[s], occurrences: expansions[s]}
+
+		for _, o := range expansions[s] {
+			results[o.day].DayEntries = append(results[o.day].DayEntries, newResultEntry(&p.slots[s].tx, o))
+			p.daySlots[o.day] = append(p.daySlots[o.day], s)
+		}
+	}
+
+	totals := runningTotals{balance: startBalance}
+
+	for d := range results {
+		totals.addDay(&results[d], results[d].DayEntries)
+	}
+
+	return p, nil
+}
+
+// Results returns the current results. Later changes to the Projection don't
+// affect the returned slice.
+func (p *Projection) Results() []Result {
+	return slices.Clone(p.results)
+}
+
+// TXs returns a copy of the transactions currently in the Projection, in
+// order.
+func (p *Projection) TXs() []TX {
+	tx := []TX{}
+
+	for i := range p.slots {
+		if !p.slots[i].removed {
+			tx = append(tx, p.slots[i].tx)
+		}
+	}
+
+	return tx
+}
+
+// AddTX adds a transaction after all of the others.
+func (p *Projection) AddTX(tx TX) error {
+	occurrences, err := p.expand(&tx)
+	if err != nil {
+		return err
+	}
+
+	p.slots = append(p.slots, projectionSlot{tx: tx})
+	p.setOccurrences(len(p.slots)-1, occurrences)
+
+	return nil
+}
+
+// UpdateTX replaces the first transaction that has the same ID as tx. If tx
+// can't be expanded, such as when its RRule is invalid, the Projection is left
+// unchanged.
+func (p *Projection) UpdateTX(tx TX) error {
+	s := p.find(tx.ID)
+	if s < 0 {
+		return ErrTXNotFound
+	}
+
+	occurrences, err := p.expand(&tx)
+	if err != nil {
+		return err
+	}
+
+	p.slots[s].tx = tx
+	p.setOccurrences(s, occurrences)
+
+	return nil
+}
+
+// RemoveTX removes the first transaction with the provided id.
+func (p *Projection) RemoveTX(id string) error {
+	s := p.find(id)
+	if s < 0 {
+		return ErrTXNotFound
+	}
+
+	p.slots[s].removed = true
+	p.setOccurrences(s, nil)
+	p.slots[s].tx = TX{}
+
+	return nil
+}
+
+// find returns the slot of the first transaction with the provided id, or -1.
+func (p *Projection) find(id string) int {
+	for s := range p.slots {
+		if !p.slots[s].removed && p.slots[s].tx.ID == id {
+			return s
+		}
+	}
+
+	return -1
+}
+
+// expand returns the occurrences of txi within the Projection's window.
+func (p *Projection) expand(txi *TX) ([]dayOccurrence, error) {
+	if !txi.Active {
+		return nil, nil
+	}
+
+	return expandTX(txi, p.results, p.startDate, p.endDate)
+}
+
+// setOccurrences replaces the cached occurrences of slot s, recalculates every
+// day that the slot occurred on before or occurs on now, and then updates the
+// running totals from the first of those days onwards.
+func (p *Projection) setOccurrences(s int, occurrences []dayOccurrence) {
+	previous := p.slots[s].occurrences
+	p.slots[s].occurrences = occurrences
+	p.index = nil
+
+	first := len(p.results)
+
+	// both are in day order, so walking them together visits each day once
+	i, j := 0, 0
+	for i < len(previous) || j < len(occurrences) {
+		var o *dayOccurrence
+
+		day := 0
+
+		switch {
+		case j == len(occurrences) || (i < len(previous) && previous[i].day < occurrences[j].day):
+			day = previous[i].day
+			i++
+		case i == len(previous) || occurrences[j].day < previous[i].day:
+			day = occurrences[j].day
+			o = &occurrences[j]
+			j++
+		default:
+			day = occurrences[j].day
+			o = &occurrences[j]
+			i++
+			j++
+		}
+
+		p.setDayEntry(day, s, o)
+		first = min(first, day)
+	}
+
+	if first == len(p.results) {
+		return
+	}
+
+	totals := runningTotals{balance: p.startBalance}
+
+	if first > 0 {
+		prev := &p.results[first-1]
+		totals = runningTotals{
+			balance:            prev.Balance,
+			diff:               prev.DiffFromStart,
+			cumulativeIncome:   prev.CumulativeIncome,
+			cumulativeExpenses: prev.CumulativeExpenses,
+		}
+	}
+
+	for d := first; d < len(p.results); d++ {
+		totals.carryDay(&p.results[d])
+	}
+}
+
+// setDayEntry replaces slot s's entry on a single day with the occurrence o,
+// or removes it if o is nil, and recalculates that day's own values. New
+// slices are built rather than modifying the existing ones, so that results
+// returned earlier stay the same.
+func (p *Projection) setDayEntry(day int, s int, o *dayOccurrence) {
+	slots := p.daySlots[day]
+	r := &p.results[day]
+
+	pos, found := slices.BinarySearch(slots, s)
+
+	end := pos
+	if found {
+		end++
+	}
+
+	var (
+		daySlots []int
+		entries  []ResultEntry
+	)
+
+	daySlots = append(daySlots, slots[:pos]...)
+	entries = append(entries, r.DayEntries[:pos]...)
+
+	if o != nil {
+		daySlots = append(daySlots, s)
+		entries = append(entries, newResultEntry(&p.slots[s].tx, *o))
+	}
+
+	daySlots = append(daySlots, slots[end:]...)
+	entries = append(entries, r.DayEntries[end:]...)
+
+	p.daySlots[day] = daySlots
+
+	// the running totals are fixed up afterwards
+	*r = Result{Record: r.Record, Date: r.Date}
+
+	var totals runningTotals
+	totals.addDay(r, entries)
+}
@@ -0,0 +1,141 @@
+package fplib_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestProjection(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+	txs := getBenchmarkTXs(50)
+	txs[3].Active = false
+
+	p, err := fpl.NewProjection(txs, start, end, 10000)
+	if err != nil {
+		t.Logf("NewProjection failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	// the projection must always match a full recalculation
+	check := func(step string) {
+		want, err := fpl.GetResults(p.TXs(), start, end, 10000, nil)
+		if err != nil {
+			t.Logf("%v: GetResults failed: %v", step, err.Error())
+			t.FailNow()
+		}
+
+		if !reflect.DeepEqual(p.Results(), want) {
+			t.Logf("%v: results differ from GetResults", step)
+			t.FailNow()
+		}
+	}
+
+	check("new")
+
+	before := p.Results()
+	beforeBalance := before[len(before)-1].Balance
+
+	tests := []struct {
+		step   string
+		change func() error
+	}{
+		{"update amount", func() error {
+			tx := txs[10]
+			tx.Amount = -123456
+
+			return p.UpdateTX(tx)
+		}},
+		{"update name and day", func() error {
+			tx := txs[20]
+			tx.Name = "Renamed"
+			tx.StartsDay = 15
+
+			return p.UpdateTX(tx)
+		}},
+		{"activate", func() error {
+			tx := txs[3]
+			tx.Active = true
+
+			return p.UpdateTX(tx)
+		}},
+		{"deactivate", func() error {
+			tx := txs[4]
+			tx.Active = false
+
+			return p.UpdateTX(tx)
+		}},
+		{"add", func() error {
+			tx := txs[0]
+			tx.ID = "new"
+			tx.Amount = 999
+
+			return p.AddTX(tx)
+		}},
+		{"remove", func() error {
+			return p.RemoveTX(txs[0].ID)
+		}},
+		{"remove added", func() error {
+			return p.RemoveTX("new")
+		}},
+		{"add after removal", func() error {
+			tx := txs[1]
+			tx.ID = "newer"
+			tx.StartsYear = 2026
+
+			return p.AddTX(tx)
+		}},
+	}
+
+	for _, test := range tests {
+		err := test.change()
+		if err != nil {
+			t.Logf("%v: failed: %v", test.step, err.Error())
+			t.FailNow()
+		}
+
+		check(test.step)
+	}
+
+	// results returned earlier aren't affected by later changes
+	if before[len(before)-1].Balance != beforeBalance {
+		t.Logf("earlier results were modified")
+		t.FailNow()
+	}
+
+	// unknown transactions
+	if err := p.UpdateTX(fpl.TX{ID: "missing"}); !errors.Is(err, fpl.ErrTXNotFound) {
+		t.Logf("expected ErrTXNotFound when updating, got %v", err)
+		t.FailNow()
+	}
+
+	if err := p.RemoveTX(txs[0].ID); !errors.Is(err, fpl.ErrTXNotFound) {
+		t.Logf("expected ErrTXNotFound when removing twice, got %v", err)
+		t.FailNow()
+	}
+
+	// a transaction that can't be expanded leaves the projection unchanged
+	{
+		tx := txs[30]
+		tx.RRule = "invalid"
+
+		if err := p.UpdateTX(tx); err == nil {
+			t.Logf("expected an error for an invalid rrule")
+			t.FailNow()
+		}
+
+		check("invalid update")
+	}
+
+	if _, err := fpl.NewProjection(txs, end, start, 0); err == nil {
+		t.Logf("expected an error when the start date is after the end date")
+		t.FailNow()
+	}
+}
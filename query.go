@@ -0,0 +1,180 @@
+package fplib
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"slices"
+	"sort"
+	"time"
+)
+
+// ErrOutsideWindow is returned when a Projection is queried for a date that
+// isn't within its window.
+var ErrOutsideWindow = errors.New("date is outside of the projection's window")
+
+// balanceIndex answers balance queries over a fixed set of results without
+// scanning them.
+type balanceIndex struct {
+	// sparse[k][i] is the index of the day with the lowest balance from day i
+	// to day i+2^k-1, inclusive, preferring the earliest day on ties.
+	sparse [][]int
+	// prefixMin[i] is the lowest balance from the first day to day i,
+	// inclusive, so it never increases.
+	prefixMin []int
+}
+
+// newBalanceIndex builds a balanceIndex for the provided results in
+// O(n log n) time.
+func newBalanceIndex(results []Result) *balanceIndex {
+	n := len(results)
+	b := &balanceIndex{
+		sparse:    [][]int{make([]int, n)},
+		prefixMin: make([]int, n),
+	}
+
+	for i := range results {
+		b.sparse[0][i] = i
+
+		b.prefixMin[i] = results[i].Balance
+		if i > 0 {
+			b.prefixMin[i] = min(b.prefixMin[i], b.prefixMin[i-1])
+		}
+	}
+
+	for k := 1; 1<<k <= n; k++ {
+		prev := b.sparse[k-1]
+		half := 1 << (k - 1)
+		level := make([]int, n-1<<k+1)
+
+		for i := range level {
+			level[i] = lowerBalance(results, prev[i], prev[i+half])
+		}
+
+		b.sparse = append(b.sparse, level)
+	}
+
+	return b
+}
+
+// lowest returns the index of the day with the lowest balance from day from to
+// day to, inclusive, in constant time.
+func (b *balanceIndex) lowest(results []Result, from int, to int) int {
+	k := bits.Len(uint(to-from+1)) - 1
+
+	return lowerBalance(results, b.sparse[k][from], b.sparse[k][to-1<<k+1])
+}
+
+// lowerBalance returns whichever of days i and j has the lower balance, or the
+// earlier of the two if they're the same.
+func lowerBalance(results []Result, i int, j int) int {
+	if results[j].Balance < results[i].Balance || (results[j].Balance == results[i].Balance && j < i) {
+		return j
+	}
+
+	return i
+}
+
+// getIndex returns the balance index for the current results, building it if
+// the results have changed since it was last built.
+func (p *Projection) getIndex() *balanceIndex {
+	if p.index == nil {
+		p.index = newBalanceIndex(p.results)
+	}
+
+	return p.index
+}
+
+// dayOf returns the index of the day that contains date, in the location of
+// the Projection's start date, and whether it's within the window.
+func (p *Projection) dayOf(date time.Time) (int, bool) {
+	first := p.results[0].Date
+	day := countDays(first, date.In(first.Location())) - 1
+
+	return day, day >= 0 && day < len(p.results)
+}
+
+// getDayRange returns the indexes of the days that contain from and to,
+// limited to the Projection's window. ErrOutsideWindow is returned if none of
+// the days between them are within the window.
+func (p *Projection) getDayRange(from time.Time, to time.Time) (int, int, error) {
+	first, _ := p.dayOf(from)
+	last, _ := p.dayOf(to)
+
+	if first > last {
+		return 0, 0, fmt.Errorf("start date is after end date: %v vs %v", from, to)
+	}
+
+	first = max(first, 0)
+	last = min(last, len(p.results)-1)
+
+	if first > last {
+		return 0, 0, ErrOutsideWindow
+	}
+
+	return first, last, nil
+}
+
+// BalanceOn returns the balance at the end of the day that contains date.
+func (p *Projection) BalanceOn(date time.Time) (int, error) {
+	day, ok := p.dayOf(date)
+	if !ok {
+		return 0, ErrOutsideWindow
+	}
+
+	return p.results[day].Balance, nil
+}
+
+// TransactionsOn returns a copy of the entries on the day that contains date.
+func (p *Projection) TransactionsOn(date time.Time) ([]ResultEntry, error) {
+	day, ok := p.dayOf(date)
+	if !ok {
+		return nil, ErrOutsideWindow
+	}
+
+	return slices.Clone(p.results[day].DayEntries), nil
+}
+
+// Range returns a copy of the results for every day from the one that contains
+// from to the one that contains to, inclusive. Days outside of the
+// Projection's window are left out, so the result may be empty.
+func (p *Projection) Range(from time.Time, to time.Time) ([]Result, error) {
+	first, last, err := p.getDayRange(from, to)
+	if errors.Is(err, ErrOutsideWindow) {
+		return []Result{}, nil
+	} else if err != nil {
+		return []Result{}, err
+	}
+
+	return slices.Clone(p.results[first : last+1]), nil
+}
+
+// MinBalance returns the result with the lowest balance from the day that
+// contains from to the day that contains to, inclusive, preferring the
+// earliest if several days share it. The query takes constant time once the
+// index has been built, which happens on the first query after the Projection
+// changes.
+func (p *Projection) MinBalance(from time.Time, to time.Time) (Result, error) {
+	first, last, err := p.getDayRange(from, to)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return p.results[p.getIndex().lowest(p.results, first, last)], nil
+}
+
+// FirstDateBelow returns the first result whose balance is below x, found by
+// binary search over the lowest balance so far. ok is false if the balance
+// never drops below x.
+func (p *Projection) FirstDateBelow(x int) (r Result, ok bool) {
+	prefixMin := p.getIndex().prefixMin
+
+	day := sort.Search(len(prefixMin), func(i int) bool {
+		return prefixMin[i] < x
+	})
+	if day == len(prefixMin) {
+		return Result{}, false
+	}
+
+	return p.results[day], true
+}
@@ -0,0 +1,147 @@
+package fplib_test
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestProjectionQueries(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, time.December, 31, 0, 0, 0, 0, time.UTC)
+	txs := getBenchmarkTXs(40)
+
+	p, err := fpl.NewProjection(txs, start, end, 500000)
+	if err != nil {
+		t.Logf("NewProjection failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	// every query is checked against a linear scan, before and after a change
+	check := func(step string) {
+		results := p.Results()
+		r := rand.New(rand.NewSource(1))
+
+		for range 200 {
+			i := r.Intn(len(results))
+			j := i + r.Intn(len(results)-i)
+			from := results[i].Date.Add(13 * time.Hour)
+			to := results[j].Date
+
+			want := results[i]
+			for _, result := range results[i : j+1] {
+				if result.Balance < want.Balance {
+					want = result
+				}
+			}
+
+			got, err := p.MinBalance(from, to)
+			if err != nil || !reflect.DeepEqual(got, want) {
+				t.Logf("%v: MinBalance(%v, %v) got %v (%v) but wanted %v", step, i, j, got.Balance, err, want.Balance)
+				t.FailNow()
+			}
+
+			gotRange, err := p.Range(from, to)
+			if err != nil || !reflect.DeepEqual(gotRange, results[i:j+1]) {
+				t.Logf("%v: Range(%v, %v) failed: %v", step, i, j, err)
+				t.FailNow()
+			}
+
+			balance, err := p.BalanceOn(from)
+			if err != nil || balance != results[i].Balance {
+				t.Logf("%v: BalanceOn(%v) got %v but wanted %v", step, i, balance, results[i].Balance)
+				t.FailNow()
+			}
+
+			entries, err := p.TransactionsOn(to)
+			if err != nil || !reflect.DeepEqual(entries, results[j].DayEntries) {
+				t.Logf("%v: TransactionsOn(%v) failed: %v", step, j, err)
+				t.FailNow()
+			}
+
+			x := results[i].Balance
+
+			got, ok := p.FirstDateBelow(x)
+			for _, result := range results {
+				if result.Balance < x {
+					if !ok || got.Record != result.Record {
+						t.Logf("%v: FirstDateBelow(%v) got %v but wanted %v", step, x, got.Record, result.Record)
+						t.FailNow()
+					}
+
+					break
+				}
+			}
+		}
+	}
+
+	check("new")
+
+	tx := txs[7]
+	tx.Amount = -9000000
+
+	if err := p.UpdateTX(tx); err != nil {
+		t.Logf("UpdateTX failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	check("updated")
+
+	// balances that never drop low enough
+	if _, ok := p.FirstDateBelow(-1 << 62); ok {
+		t.Logf("expected no date below the lowest possible balance")
+		t.FailNow()
+	}
+
+	// dates in other locations use the day they fall on in the start date's
+	// location
+	{
+		tokyo := time.FixedZone("UTC+9", 9*60*60)
+		results := p.Results()
+
+		balance, err := p.BalanceOn(time.Date(2025, time.January, 2, 8, 0, 0, 0, tokyo))
+		if err != nil || balance != results[0].Balance {
+			t.Logf("BalanceOn in another location got %v (%v) but wanted %v", balance, err, results[0].Balance)
+			t.FailNow()
+		}
+	}
+
+	// dates outside of the window
+	{
+		before := start.AddDate(0, 0, -1)
+		after := end.AddDate(0, 0, 1)
+
+		if _, err := p.BalanceOn(before); !errors.Is(err, fpl.ErrOutsideWindow) {
+			t.Logf("expected ErrOutsideWindow from BalanceOn, got %v", err)
+			t.FailNow()
+		}
+
+		if _, err := p.TransactionsOn(after); !errors.Is(err, fpl.ErrOutsideWindow) {
+			t.Logf("expected ErrOutsideWindow from TransactionsOn, got %v", err)
+			t.FailNow()
+		}
+
+		if _, err := p.MinBalance(after, after.AddDate(1, 0, 0)); !errors.Is(err, fpl.ErrOutsideWindow) {
+			t.Logf("expected ErrOutsideWindow from MinBalance, got %v", err)
+			t.FailNow()
+		}
+
+		got, err := p.Range(before.AddDate(-1, 0, 0), start)
+		if err != nil || len(got) != 1 {
+			t.Logf("expected Range to be limited to the window, got %v results (%v)", len(got), err)
+			t.FailNow()
+		}
+
+		if _, err := p.Range(end, start); err == nil {
+			t.Logf("expected an error from Range when from is after to")
+			t.FailNow()
+		}
+	}
+}
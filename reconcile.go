@@ -0,0 +1,259 @@
+package fplib
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// PostedTX is an actual transaction that was posted to a bank account, such
+// as one imported from a bank's CSV export.
+type PostedTX struct {
+	Date   time.Time `yaml:"date"`
+	Amount int       `yaml:"amount"` // in cents; 500 = $5.00
+	Name   string    `yaml:"name"`
+	// An optional identifier from the bank.
+	ID string `yaml:"id"`
+}
+
+// ProjectedOccurrence is a single projected transaction occurrence from a set
+// of results.
+type ProjectedOccurrence struct {
+	Date  time.Time
+	Entry ResultEntry
+}
+
+// ReconciledMatch pairs a projected occurrence with the posted transaction
+// that it was matched to.
+type ReconciledMatch struct {
+	Projected ProjectedOccurrence
+	Posted    PostedTX
+	// The posted amount minus the projected amount.
+	AmountVariance int
+	// The number of days that the posted transaction was after (or, if
+	// negative, before) the projected occurrence.
+	DayOffset int
+	// How similar the names are, from 0 (nothing in common) to 1 (the same).
+	NameSimilarity float64
+}
+
+// ReconcileOptions controls how Reconcile matches projected occurrences to
+// posted transactions. A projected occurrence and a posted transaction can
+// only be matched if all of the criteria are met.
+type ReconcileOptions struct {
+	// The largest difference in amount, in cents, that can be matched.
+	AmountTolerance int
+	// The largest number of days apart, in either direction, that can be
+	// matched.
+	DateWindow int
+	// The smallest name similarity, from 0 to 1, that can be matched. Zero
+	// disregards names entirely.
+	MinNameSimilarity float64
+}
+
+// ReconciliationReport is the output of Reconcile.
+type ReconciliationReport struct {
+	// Every projected occurrence that was matched to a posted transaction.
+	Matched []ReconciledMatch
+	// The subset of Matched where the amounts differ.
+	AmountVariances []ReconciledMatch
+	// Projected occurrences that weren't matched, such as a recurring bill
+	// that never cleared.
+	Missed []ProjectedOccurrence
+	// Posted transactions that weren't matched to anything in the plan.
+	Unexpected []PostedTX
+}
+
+// Reconcile matches the projected transaction occurrences in the provided
+// results against actual posted transactions, to determine whether recurring
+// bills actually cleared and how accurate the plan is. The results should
+// cover the same window as the posted transactions.
+//
+// Matching is greedy: the pairs with the most similar names are matched
+// first, followed by the pairs closest in date and then in amount.
+func Reconcile(results []Result, posted []PostedTX, opts ReconcileOptions) ReconciliationReport {
+	projected := []ProjectedOccurrence{}
+
+	for _, r := range results {
+		for _, e := range r.DayEntries {
+			projected = append(projected, ProjectedOccurrence{Date: r.Date, Entry: e})
+		}
+	}
+
+	type candidate struct {
+		p, q  int
+		match ReconciledMatch
+	}
+
+	// posted transactions in date order, so that only the ones within the
+	// date window of each projected occurrence need to be compared to it
+	byDate := make([]int, len(posted))
+	for q := range byDate {
+		byDate[q] = q
+	}
+
+	sort.SliceStable(byDate, func(i, j int) bool {
+		return posted[byDate[i]].Date.Before(posted[byDate[j]].Date)
+	})
+
+	candidates := []candidate{}
+
+	for p := range projected {
+		dayOffset := func(q int) int {
+			return countDays(projected[p].Date, posted[q].Date) - 1
+		}
+
+		first := sort.Search(len(byDate), func(i int) bool {
+			return dayOffset(byDate[i]) >= -opts.DateWindow
+		})
+
+		for _, q := range byDate[first:] {
+			offset := dayOffset(q)
+			if offset > opts.DateWindow {
+				break
+			}
+
+			// the name is compared last, since it's the most expensive check
+			variance := posted[q].Amount - projected[p].Entry.Amount
+			if abs(variance) > opts.AmountTolerance {
+				continue
+			}
+
+			similarity := nameSimilarity(projected[p].Entry.Name, posted[q].Name)
+			if similarity < opts.MinNameSimilarity {
+				continue
+			}
+
+			candidates = append(candidates, candidate{p, q, ReconciledMatch{
+				Projected:      projected[p],
+				Posted:         posted[q],
+				AmountVariance: variance,
+				DayOffset:      offset,
+				NameSimilarity: similarity,
+			}})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].match, candidates[j].match
+		if a.NameSimilarity != b.NameSimilarity {
+			return a.NameSimilarity > b.NameSimilarity
+		}
+
+		if abs(a.DayOffset) != abs(b.DayOffset) {
+			return abs(a.DayOffset) < abs(b.DayOffset)
+		}
+
+		if abs(a.AmountVariance) != abs(b.AmountVariance) {
+			return abs(a.AmountVariance) < abs(b.AmountVariance)
+		}
+
+		// ties go to the earliest provided, regardless of the order that the
+		// candidates were found in
+		if candidates[i].p != candidates[j].p {
+			return candidates[i].p < candidates[j].p
+		}
+
+		return candidates[i].q < candidates[j].q
+	})
+
+	report := ReconciliationReport{
+		Matched:         []ReconciledMatch{},
+		AmountVariances: []ReconciledMatch{},
+		Missed:          []ProjectedOccurrence{},
+		Unexpected:      []PostedTX{},
+	}
+
+	matchedProjected := make(map[int]bool)
+	matchedPosted := make(map[int]bool)
+
+	for _, c := range candidates {
+		if matchedProjected[c.p] || matchedPosted[c.q] {
+			continue
+		}
+
+		matchedProjected[c.p] = true
+		matchedPosted[c.q] = true
+
+		report.Matched = append(report.Matched, c.match)
+	}
+
+	// keep the report in date order rather than in matching order
+	sort.SliceStable(report.Matched, func(i, j int) bool {
+		return report.Matched[i].Projected.Date.Before(report.Matched[j].Projected.Date)
+	})
+
+	for _, m := range report.Matched {
+		if m.AmountVariance != 0 {
+			report.AmountVariances = append(report.AmountVariances, m)
+		}
+	}
+
+	for p := range projected {
+		if !matchedProjected[p] {
+			report.Missed = append(report.Missed, projected[p])
+		}
+	}
+
+	for q := range posted {
+		if !matchedPosted[q] {
+			report.Unexpected = append(report.Unexpected, posted[q])
+		}
+	}
+
+	return report
+}
+
+// nameSimilarity compares two names using the Sørensen–Dice coefficient of
+// their letter and digit bigrams, ignoring case, punctuation and spacing, so
+// that "NETFLIX.COM 866-579" is still similar to "Netflix".
+func nameSimilarity(a, b string) float64 {
+	normalize := func(s string) []rune {
+		r := []rune{}
+
+		for _, c := range strings.ToLower(s) {
+			if unicode.IsLetter(c) || unicode.IsDigit(c) {
+				r = append(r, c)
+			}
+		}
+
+		return r
+	}
+
+	ra, rb := normalize(a), normalize(b)
+
+	if len(ra) < 2 || len(rb) < 2 {
+		if string(ra) == string(rb) {
+			return 1
+		}
+
+		return 0
+	}
+
+	bigrams := make(map[string]int)
+	for i := 0; i < len(ra)-1; i++ {
+		bigrams[string(ra[i:i+2])]++
+	}
+
+	shared := 0
+
+	for i := 0; i < len(rb)-1; i++ {
+		bigram := string(rb[i : i+2])
+		if bigrams[bigram] > 0 {
+			bigrams[bigram]--
+			shared++
+		}
+	}
+
+	return 2 * float64(shared) / float64(len(ra)-1+len(rb)-1)
+}
+
+// abs returns the absolute value of a.
+func abs(a int) int {
+	if a < 0 {
+		return -a
+	}
+
+	return a
+}
@@ -0,0 +1,106 @@
+package fplib_test
+
+import (
+	"testing"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestReconcile(t *testing.T) {
+	t.Parallel()
+
+	txs := []fpl.TX{
+		monthlyTX("rent", "Rent", -150000, 1),
+		monthlyTX("netflix", "Netflix", -1549, 10),
+		monthlyTX("power", "Electric bill", -8000, 20),
+	}
+
+	results, err := fpl.GetResults(txs, date(2025, 1, 1), date(2025, 2, 28), 0, nil)
+	if err != nil {
+		t.Logf("GetResults failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	posted := []fpl.PostedTX{
+		{Date: date(2025, 1, 2), Amount: -150000, Name: "RENT PAYMENT"},
+		{Date: date(2025, 1, 10), Amount: -1549, Name: "NETFLIX.COM 866-579"},
+		// the power bill was higher than planned and a day early
+		{Date: date(2025, 1, 19), Amount: -9250, Name: "Electric"},
+		{Date: date(2025, 2, 1), Amount: -150000, Name: "RENT PAYMENT"},
+		// a price increase that is outside of the amount tolerance
+		{Date: date(2025, 2, 10), Amount: -4999, Name: "NETFLIX.COM 866-579"},
+		{Date: date(2025, 2, 20), Amount: -7900, Name: "ELECTRIC BILL"},
+		{Date: date(2025, 2, 14), Amount: -6000, Name: "Flowers"},
+	}
+
+	got := fpl.Reconcile(results, posted, fpl.ReconcileOptions{
+		AmountTolerance:   2000,
+		DateWindow:        3,
+		MinNameSimilarity: 0.3,
+	})
+
+	tests := []struct {
+		name      string
+		got, want int
+	}{
+		{"matched", len(got.Matched), 5},
+		{"amount variances", len(got.AmountVariances), 2},
+		{"missed", len(got.Missed), 1},
+		{"unexpected", len(got.Unexpected), 2},
+	}
+
+	for _, test := range tests {
+		if test.got != test.want {
+			t.Logf("wrong number of %v: got %v, want %v", test.name, test.got, test.want)
+			t.FailNow()
+		}
+	}
+
+	first := got.Matched[0]
+	if first.Projected.Entry.ID != "rent" || first.DayOffset != 1 || first.AmountVariance != 0 {
+		t.Logf("wrong first match: %+v", first)
+		t.FailNow()
+	}
+
+	power := got.AmountVariances[0]
+	if power.Projected.Entry.ID != "power" || power.DayOffset != -1 || power.AmountVariance != -1250 {
+		t.Logf("wrong amount variance: %+v", power)
+		t.FailNow()
+	}
+
+	if got.Missed[0].Entry.ID != "netflix" || !got.Missed[0].Date.Equal(date(2025, 2, 10)) {
+		t.Logf("wrong missed occurrence: %+v", got.Missed[0])
+		t.FailNow()
+	}
+
+	// without a tolerance, the exact amount and date are required
+	strict := fpl.Reconcile(results, posted, fpl.ReconcileOptions{})
+	if len(strict.Matched) != 2 || len(strict.Missed) != 4 || len(strict.Unexpected) != 5 {
+		t.Logf("strict reconcile failed: %v matched, %v missed, %v unexpected",
+			len(strict.Matched), len(strict.Missed), len(strict.Unexpected))
+		t.FailNow()
+	}
+
+	// the date window is inclusive in both directions, and ties go to the
+	// first posted transaction provided rather than the earliest one
+	edges := []fpl.PostedTX{
+		{Date: date(2025, 1, 5), Amount: -150000, Name: "Rent"},
+		{Date: date(2024, 12, 28), Amount: -150000, Name: "Rent"},
+	}
+
+	windows := []struct {
+		window, want int
+	}{
+		{3, 0},
+		{4, 1},
+	}
+
+	for _, test := range windows {
+		got := fpl.Reconcile(results, edges, fpl.ReconcileOptions{DateWindow: test.window})
+		if len(got.Matched) != test.want || (test.want > 0 && !got.Matched[0].Posted.Date.Equal(edges[0].Date)) {
+			t.Logf("window %v: got matches %+v, wanted %v matching %v", test.window, got.Matched, test.want, edges[0].Date)
+			t.FailNow()
+		}
+	}
+}
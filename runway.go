@@ -0,0 +1,133 @@
+package fplib
+
+import (
+	"context"
+	"time"
+)
+
+// Runway is the outcome of GetRunway.
+type Runway struct {
+	// Whether the balance drops below the floor before the horizon.
+	Exhausted bool
+	// The first day that the balance is below the floor. This is the zero
+	// time if Exhausted is false.
+	ExhaustedOn time.Time
+	// The number of days from when the income stops (or from the start date,
+	// if it doesn't stop or stops before it) until ExhaustedOn, or zero if it
+	// was exhausted before the income stopped. If the money never runs out,
+	// this is the number of days until the horizon instead.
+	Days int
+	// The results that the runway was determined from.
+	Results []Result
+}
+
+// GetRunway determines how long the balance lasts before it drops below
+// floor, such as when budgeting through a job loss. If incomeStops is not
+// the zero time, every income transaction (any transaction with a positive
+// Amount or scheduled amount) stops occurring on and after that date. The
+// projection runs from startDate through horizon.
+func GetRunway(tx []TX, startDate time.Time, horizon time.Time, startBalance int, floor int, incomeStops time.Time, statusHook func(status string)) (Runway, error) {
+	return GetRunwayWithOptions(context.Background(), tx, startDate, horizon, floor, incomeStops, ResultsOptions{
+		StartBalance: startBalance,
+		Progress:     StatusHookReporter(statusHook),
+	})
+}
+
+// GetRunwayWithOptions is the same as GetRunway, except that the start balance
+// and progress reporting are controlled by opts, and if ctx is cancelled or its
+// deadline passes before the projection is done, ctx.Err() is returned.
+func GetRunwayWithOptions(ctx context.Context, tx []TX, startDate time.Time, horizon time.Time, floor int, incomeStops time.Time, opts ResultsOptions) (Runway, error) {
+	if !incomeStops.IsZero() {
+		stopped := make([]TX, len(tx))
+
+		for i, txi := range tx {
+			if isIncome(&txi) {
+				var err error
+
+				txi, err = stopTX(txi, startDate, horizon, incomeStops)
+				if err != nil {
+					return Runway{}, err
+				}
+			}
+
+			stopped[i] = txi
+		}
+
+		tx = stopped
+	}
+
+	results, err := GetResultsWithOptions(ctx, tx, startDate, horizon, opts)
+	if err != nil {
+		return Runway{}, err
+	}
+
+	from := startDate
+	if incomeStops.After(startDate) {
+		from = incomeStops
+	}
+
+	runway := Runway{Results: results}
+
+	for _, r := range results {
+		if r.Balance < floor {
+			runway.Exhausted = true
+			runway.ExhaustedOn = r.Date
+			runway.Days = max(countDays(from, r.Date)-1, 0)
+
+			return runway, nil
+		}
+	}
+
+	runway.Days = countDays(from, horizon) - 1
+
+	return runway, nil
+}
+
+// isIncome determines whether a transaction ever adds to the balance.
+func isIncome(txi *TX) bool {
+	if txi.Amount > 0 {
+		return true
+	}
+
+	for _, sa := range txi.AmountSchedule {
+		if sa.Amount > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// stopTX returns a copy of txi that doesn't occur on or after the day that
+// stops falls on. Transactions without an RRule end the day before, and
+// RRule occurrences from then through horizon are excluded from the rule.
+func stopTX(txi TX, startDate time.Time, horizon time.Time, stops time.Time) (TX, error) {
+	stops = time.Date(stops.Year(), stops.Month(), stops.Day(), 0, 0, 0, 0, stops.Location())
+
+	if txi.RRule == "" {
+		last := stops.AddDate(0, 0, -1)
+		ends := time.Date(txi.EndsYear, time.Month(txi.EndsMonth), txi.EndsDay, 0, 0, 0, 0, time.UTC)
+		emptyDate := time.Date(0, time.Month(0), 0, 0, 0, 0, 0, time.UTC)
+
+		if ends == emptyDate || ends.After(last) {
+			txi.EndsYear, txi.EndsMonth, txi.EndsDay = last.Year(), int(last.Month()), last.Day()
+		}
+
+		return txi, nil
+	}
+
+	s, err := getRecurrence(&txi, startDate, horizon)
+	if err != nil {
+		return TX{}, err
+	}
+
+	exdates := s.Between(stops, horizon.AddDate(0, 0, 1), true)
+	if len(exdates) == 0 {
+		return txi, nil
+	}
+
+	s.SetExDates(append(s.GetExDate(), exdates...))
+	txi.RRule = s.String()
+
+	return txi, nil
+}
@@ -0,0 +1,99 @@
+package fplib_test
+
+import (
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestGetRunway(t *testing.T) {
+	t.Parallel()
+
+	txs := []fpl.TX{monthlyTX("", "Rent", -100000, 1), monthlyTX("", "Paycheck", 150000, 15)}
+	start, horizon := date(2025, 1, 1), date(2026, 12, 31)
+
+	tests := []struct {
+		incomeStops     time.Time
+		balance, floor  int
+		wantExhausted   bool
+		wantExhaustedOn time.Time
+		wantDays        int
+	}{
+		// income never stops, so money never runs out
+		{time.Time{}, 100000, 0, false, time.Time{}, 729},
+		// the last paycheck is on May 15th, so the balance is 350000 after
+		// it, and rent drains it below zero on September 1st
+		{date(2025, 6, 1), 100000, 0, true, date(2025, 9, 1), 92},
+		// with a lower floor, it runs out later
+		{date(2025, 6, 1), 100000, -60000, true, date(2025, 10, 1), 122},
+		// it runs out before the income even stops
+		{date(2025, 6, 1), 100000, 100001, true, date(2025, 1, 1), 0},
+		// income stops before the start, so days count from the start
+		{date(2024, 6, 1), 100000, 0, true, date(2025, 2, 1), 31},
+	}
+
+	for i, test := range tests {
+		got, err := fpl.GetRunway(txs, start, horizon, test.balance, test.floor, test.incomeStops, nil)
+		if err != nil {
+			t.Logf("test %v threw error when it wasn't supposed to: %v", i, err.Error())
+			t.FailNow()
+		}
+
+		if got.Exhausted != test.wantExhausted || !got.ExhaustedOn.Equal(test.wantExhaustedOn) || got.Days != test.wantDays {
+			t.Logf("test %v failed: got %v on %v after %v days, but wanted %v on %v after %v days",
+				i, got.Exhausted, got.ExhaustedOn, got.Days, test.wantExhausted, test.wantExhaustedOn, test.wantDays)
+			t.FailNow()
+		}
+	}
+
+	// the caller's transactions must not be modified
+	if len(txs[1].AmountSchedule) != 0 || txs[1].EndsYear != 0 {
+		t.Logf("GetRunway modified the provided transactions")
+		t.FailNow()
+	}
+
+	// a raise that takes effect after the income stops must not revive it,
+	// and an RRule income stops the same way
+	raised := monthlyTX("", "Paycheck", 150000, 15)
+	raised.AmountSchedule = []fpl.ScheduledAmount{{Effective: date(2027, 1, 1), Amount: 200000}}
+	rruled := fpl.TX{
+		Amount: 150000,
+		Name:   "Paycheck",
+		Active: true,
+		RRule:  "DTSTART:20250115T000000Z\nRRULE:FREQ=MONTHLY;BYMONTHDAY=15",
+	}
+
+	for i, income := range []fpl.TX{raised, rruled} {
+		stops := date(2026, 6, 1)
+
+		got, err := fpl.GetRunway([]fpl.TX{monthlyTX("", "Rent", -100000, 1), income}, start, date(2027, 12, 31), 100000, 0, stops, nil)
+		if err != nil {
+			t.Logf("stopped income %v threw error when it wasn't supposed to: %v", i, err.Error())
+			t.FailNow()
+		}
+
+		// the last paycheck is on May 15th, 2026, leaving 950000, which rent
+		// drains below zero on March 1st, 2027
+		if !got.Exhausted || !got.ExhaustedOn.Equal(date(2027, 3, 1)) {
+			t.Logf("stopped income %v ran out on %v, but wanted %v", i, got.ExhaustedOn, date(2027, 3, 1))
+			t.Fail()
+		}
+
+		for _, r := range got.Results {
+			for _, e := range r.DayEntries {
+				if e.Name == "Paycheck" && !r.Date.Before(stops) {
+					t.Logf("stopped income %v still occurred on %v", i, r.Date)
+					t.FailNow()
+				}
+			}
+		}
+	}
+
+	_, err := fpl.GetRunway(txs, horizon, start, 0, 0, time.Time{}, nil)
+	if err == nil {
+		t.Logf("expected error for start after horizon")
+		t.FailNow()
+	}
+}
@@ -0,0 +1,150 @@
+package fplib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// ScenarioDiff compares one scenario's transactions and results against the
+// baseline scenario's.
+type ScenarioDiff struct {
+	// The scenario's balance minus the baseline's balance, for every day.
+	Deltas []int
+	// Whether the balances differ on any day, and the first day they do.
+	Diverged        bool
+	FirstDivergence time.Time
+	// The difference between the balances on the last day.
+	EndDifference int
+	// Transactions (matched by ID) that are only in this scenario, only in
+	// the baseline, or in both but with different definitions. Changed holds
+	// this scenario's versions of the transactions.
+	Added   []TX
+	Removed []TX
+	Changed []TX
+}
+
+// ScenarioComparison is the output of CompareScenarios.
+type ScenarioComparison struct {
+	// The results of each scenario, in the order they were provided.
+	Results [][]Result
+	// The comparison of every scenario after the first against the first,
+	// so Diffs[0] compares the second scenario against the first.
+	Diffs []ScenarioDiff
+}
+
+// CompareScenarios runs GetResults for two or more sets of transactions, such
+// as "what if we cancel the gym", over the same window and compares each of
+// them against the first set, which is the baseline.
+func CompareScenarios(scenarios [][]TX, startDate time.Time, endDate time.Time, startBalance int, statusHook func(status string)) (ScenarioComparison, error) {
+	return CompareScenariosWithOptions(context.Background(), scenarios, startDate, endDate, ResultsOptions{
+		StartBalance: startBalance,
+		Progress:     StatusHookReporter(statusHook),
+	})
+}
+
+// CompareScenariosWithOptions is the same as CompareScenarios, except that the
+// start balance and progress reporting are controlled by opts, and if ctx is
+// cancelled or its deadline passes before every scenario has been projected,
+// ctx.Err() is returned. PhaseScenarios is reported before each scenario.
+func CompareScenariosWithOptions(ctx context.Context, scenarios [][]TX, startDate time.Time, endDate time.Time, opts ResultsOptions) (ScenarioComparison, error) {
+	if len(scenarios) < 2 {
+		return ScenarioComparison{}, errors.New("at least two scenarios are required")
+	}
+
+	comparison := ScenarioComparison{
+		Results: make([][]Result, len(scenarios)),
+		Diffs:   make([]ScenarioDiff, len(scenarios)-1),
+	}
+
+	progress := newProgressTracker(opts.Progress, opts.ProgressInterval)
+
+	for i, s := range scenarios {
+		progress.report(PhaseScenarios, i, len(scenarios))
+
+		r, err := getResults(ctx, s, startDate, endDate, opts.StartBalance, progress)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ScenarioComparison{}, ctxErr
+			}
+
+			return ScenarioComparison{}, fmt.Errorf("failed to get results for scenario %v: %v", i+1, err.Error())
+		}
+
+		comparison.Results[i] = r
+	}
+
+	baseline := comparison.Results[0]
+
+	for i := 1; i < len(scenarios); i++ {
+		results := comparison.Results[i]
+		diff := ScenarioDiff{Deltas: make([]int, len(results))}
+
+		for j := range results {
+			diff.Deltas[j] = results[j].Balance - baseline[j].Balance
+
+			if diff.Deltas[j] != 0 && !diff.Diverged {
+				diff.Diverged = true
+				diff.FirstDivergence = results[j].Date
+			}
+		}
+
+		if len(results) > 0 {
+			diff.EndDifference = diff.Deltas[len(results)-1]
+		}
+
+		diff.Added, diff.Removed, diff.Changed = diffTXs(scenarios[0], scenarios[i])
+		comparison.Diffs[i-1] = diff
+	}
+
+	progress.report(PhaseDone, len(scenarios), len(scenarios))
+
+	return comparison, nil
+}
+
+// diffTXs matches the transactions of two sets by ID and returns the
+// transactions that were added to b, removed from a, and changed in b.
+func diffTXs(a, b []TX) (added, removed, changed []TX) {
+	added = []TX{}
+	removed = []TX{}
+	changed = []TX{}
+
+	byID := make(map[string]TX, len(a))
+	for _, txi := range a {
+		byID[txi.ID] = txi
+	}
+
+	seen := make(map[string]bool, len(b))
+
+	for _, txi := range b {
+		seen[txi.ID] = true
+
+		original, ok := byID[txi.ID]
+		if !ok {
+			added = append(added, txi)
+		} else if !txEquivalent(original, txi) {
+			changed = append(changed, txi)
+		}
+	}
+
+	for _, txi := range a {
+		if !seen[txi.ID] {
+			removed = append(removed, txi)
+		}
+	}
+
+	return added, removed, changed
+}
+
+// txEquivalent determines whether two transactions are defined the same way,
+// ignoring fields that don't affect the results, such as timestamps and
+// whether they're selected in a table.
+func txEquivalent(a, b TX) bool {
+	a.CreatedAt, b.CreatedAt = time.Time{}, time.Time{}
+	a.UpdatedAt, b.UpdatedAt = time.Time{}, time.Time{}
+	a.Selected, b.Selected = false, false
+
+	return reflect.DeepEqual(a, b)
+}
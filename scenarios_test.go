@@ -0,0 +1,95 @@
+package fplib_test
+
+import (
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestCompareScenarios(t *testing.T) {
+	t.Parallel()
+
+	rent := monthlyTX("rent", "rent", -100000, 1)
+	gym := monthlyTX("gym", "gym", -5000, 10)
+	pay := monthlyTX("pay", "pay", 300000, 15)
+	raise := monthlyTX("pay", "pay", 330000, 15)
+	// only the timestamps differ, so this isn't a change
+	touched := gym
+	touched.UpdatedAt = date(2025, 1, 1)
+	touched.Selected = true
+
+	baseline := []fpl.TX{rent, gym, pay}
+
+	got, err := fpl.CompareScenarios(
+		[][]fpl.TX{
+			baseline,
+			{rent, pay},            // cancel the gym
+			{rent, touched, raise}, // take the new job
+			{rent, gym, pay, monthlyTX("car", "car", -20000, 20)},
+		},
+		date(2025, 1, 1), date(2025, 12, 31), 0, nil,
+	)
+	if err != nil {
+		t.Logf("CompareScenarios failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	tests := []struct {
+		firstDivergence         time.Time
+		endDifference           int
+		added, removed, changed int
+	}{
+		{date(2025, 1, 10), 12 * 5000, 0, 1, 0},
+		{date(2025, 1, 15), 12 * 30000, 0, 0, 1},
+		{date(2025, 1, 20), 12 * -20000, 1, 0, 0},
+	}
+
+	if len(got.Results) != 4 || len(got.Diffs) != len(tests) {
+		t.Logf("wrong lengths: got %v results and %v diffs", len(got.Results), len(got.Diffs))
+		t.FailNow()
+	}
+
+	for i, test := range tests {
+		diff := got.Diffs[i]
+		if !diff.Diverged || !diff.FirstDivergence.Equal(test.firstDivergence) || diff.EndDifference != test.endDifference {
+			t.Logf("test %v failed: diverged %v on %v with end difference %v", i, diff.Diverged, diff.FirstDivergence, diff.EndDifference)
+			t.FailNow()
+		}
+
+		if len(diff.Added) != test.added || len(diff.Removed) != test.removed || len(diff.Changed) != test.changed {
+			t.Logf("test %v failed: got %v added, %v removed, %v changed", i, len(diff.Added), len(diff.Removed), len(diff.Changed))
+			t.FailNow()
+		}
+
+		if len(diff.Deltas) != len(got.Results[0]) || diff.Deltas[0] != 0 {
+			t.Logf("test %v failed: wrong deltas", i)
+			t.FailNow()
+		}
+	}
+
+	// identical scenarios never diverge
+	{
+		same, err := fpl.CompareScenarios([][]fpl.TX{baseline, baseline}, date(2025, 1, 1), date(2025, 12, 31), 0, nil)
+		if err != nil || same.Diffs[0].Diverged || same.Diffs[0].EndDifference != 0 {
+			t.Logf("identical scenarios failed: %+v, %v", same.Diffs, err)
+			t.FailNow()
+		}
+	}
+
+	// invalid input
+	{
+		_, err := fpl.CompareScenarios([][]fpl.TX{baseline}, date(2025, 1, 1), date(2025, 12, 31), 0, nil)
+		if err == nil {
+			t.Logf("expected error for a single scenario")
+			t.FailNow()
+		}
+
+		_, err = fpl.CompareScenarios([][]fpl.TX{baseline, baseline}, date(2025, 12, 31), date(2025, 1, 1), 0, nil)
+		if err == nil {
+			t.Logf("expected error for start after end")
+			t.FailNow()
+		}
+	}
+}
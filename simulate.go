@@ -0,0 +1,272 @@
+package fplib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// DefaultPercentiles are the percentile bands produced by Simulate when none
+// are provided.
+var DefaultPercentiles = []float64{5, 25, 50, 75, 95}
+
+// Distribution describes how the amount of a transaction varies from one
+// occurrence to the next. Type is one of UNIFORM, NORMAL or EMPIRICAL:
+//
+//   - UNIFORM picks an amount between Min and Max, inclusive.
+//   - NORMAL picks an amount around the transaction's amount for that
+//     occurrence (see TX.GetAmountOn) with a standard deviation of StdDev.
+//   - EMPIRICAL picks one of the provided Samples at random.
+//
+// As with TX.Amount, all values are in cents.
+type Distribution struct {
+	Type    string `yaml:"type" json:"type"`
+	Min     int    `yaml:"min" json:"min"`
+	Max     int    `yaml:"max" json:"max"`
+	StdDev  int    `yaml:"stdDev" json:"stdDev"`
+	Samples []int  `yaml:"samples" json:"samples"`
+}
+
+// Sample draws a single amount from the distribution using r. The provided
+// amount is used as the mean for NORMAL distributions, and is returned as-is
+// if the distribution is unknown or lacks the values it needs, although
+// Simulate rejects such distributions up front.
+func (d *Distribution) Sample(r *rand.Rand, amount int) int {
+	switch d.Type {
+	case UNIFORM:
+		lo, hi := d.Min, d.Max
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+
+		return lo + r.Intn(hi-lo+1)
+	case NORMAL:
+		return int(math.Round(r.NormFloat64()*float64(d.StdDev) + float64(amount)))
+	case EMPIRICAL:
+		if len(d.Samples) == 0 {
+			return amount
+		}
+
+		return d.Samples[r.Intn(len(d.Samples))]
+	default:
+		return amount
+	}
+}
+
+// validate returns an error if the distribution's Type is unknown or it lacks
+// the values that its Type needs.
+func (d *Distribution) validate() error {
+	switch d.Type {
+	case UNIFORM:
+		return nil
+	case NORMAL:
+		if d.StdDev < 0 {
+			return fmt.Errorf("negative standard deviation: %v", d.StdDev)
+		}
+
+		return nil
+	case EMPIRICAL:
+		if len(d.Samples) == 0 {
+			return errors.New("at least one sample is required")
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("unknown distribution type %q, expected %v, %v or %v", d.Type, UNIFORM, NORMAL, EMPIRICAL)
+	}
+}
+
+// SimulationDay holds the percentile balance bands for a single day across
+// every run of a simulation.
+type SimulationDay struct {
+	Date time.Time
+	// Balances has one balance per requested percentile, in the same order
+	// as SimulationResult.Percentiles.
+	Balances []int
+	// The fraction of runs whose balance was below zero on this day.
+	ProbabilityBelowZero float64
+}
+
+// SimulationResult is the output of Simulate.
+type SimulationResult struct {
+	Runs        int
+	Percentiles []float64
+	Days        []SimulationDay
+	// The fraction of runs whose balance went below zero on at least one day.
+	ProbabilityBelowZero float64
+}
+
+// Simulate runs the provided number of Monte Carlo projections of the
+// transactions, drawing the amount of every occurrence of a transaction that
+// has a Distribution at random, and summarizes the balances of each day as
+// percentile bands. Transactions without a Distribution behave exactly as they
+// do in GetResults. The same seed always produces the same result.
+//
+// percentiles are values from 0 to 100; if none are provided,
+// DefaultPercentiles is used. It's the same as SimulateContext without the
+// ability to cancel it.
+func Simulate(tx []TX, startDate time.Time, endDate time.Time, startBalance int, runs int, seed int64, percentiles []float64, statusHook func(status string)) (SimulationResult, error) {
+	return SimulateContext(context.Background(), tx, startDate, endDate, startBalance, runs, seed, percentiles, statusHook)
+}
+
+// SimulateContext is the same as SimulateWithOptions, except that progress is
+// reported to statusHook as human-readable statuses every
+// DefaultProgressInterval items. statusHook may be nil.
+func SimulateContext(ctx context.Context, tx []TX, startDate time.Time, endDate time.Time, startBalance int, runs int, seed int64, percentiles []float64, statusHook func(status string)) (SimulationResult, error) {
+	return SimulateWithOptions(ctx, tx, startDate, endDate, runs, seed, percentiles, ResultsOptions{
+		StartBalance: startBalance,
+		Progress:     StatusHookReporter(statusHook),
+	})
+}
+
+// SimulateWithOptions is the same as Simulate, except that the start balance
+// and progress reporting are controlled by opts, and if ctx is cancelled or its
+// deadline passes before the simulation is done, ctx.Err() is returned. Days
+// are reported as PhaseCalculating.
+//
+// Every run advances one day at a time alongside the others, so only the
+// current balance of each run is kept rather than every run's balance on
+// every day.
+func SimulateWithOptions(ctx context.Context, tx []TX, startDate time.Time, endDate time.Time, runs int, seed int64, percentiles []float64, opts ResultsOptions) (SimulationResult, error) {
+	startBalance := opts.StartBalance
+
+	if startDate.After(endDate) {
+		return SimulationResult{}, fmt.Errorf("start date is after end date: %v vs %v", startDate, endDate)
+	}
+
+	if runs <= 0 {
+		return SimulationResult{}, errors.New("the number of runs must be positive")
+	}
+
+	if len(percentiles) == 0 {
+		percentiles = DefaultPercentiles
+	}
+
+	for _, p := range percentiles {
+		if p < 0 || p > 100 {
+			return SimulationResult{}, fmt.Errorf("percentile out of range: %v", p)
+		}
+	}
+
+	for i := range tx {
+		if !tx[i].Active || tx[i].Distribution == nil {
+			continue
+		}
+
+		if err := tx[i].Distribution.validate(); err != nil {
+			return SimulationResult{}, fmt.Errorf("invalid distribution for tx %v: %v", tx[i].Name, err.Error())
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return SimulationResult{}, err
+	}
+
+	progress := newProgressTracker(opts.Progress, opts.ProgressInterval)
+	progress.report(PhasePreparing, 0, 0)
+
+	results := getResultDays(startDate, endDate)
+	daysLen := len(results)
+
+	// expand every occurrence once up front, since only the amounts vary
+	// between runs
+	slots, err := expandOccurrences(ctx, tx, results, startDate, endDate, progress)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+
+	type occurrence struct {
+		amount int
+		dist   *Distribution
+	}
+
+	dayOccurrences := make([][]occurrence, daysLen)
+
+	for i, occurrences := range slots {
+		for _, o := range occurrences {
+			dayOccurrences[o.day] = append(dayOccurrences[o.day], occurrence{o.amount, tx[i].Distribution})
+		}
+	}
+
+	r := rand.New(rand.NewSource(seed)) //nolint:gosec
+	// the running balance of every run, and the same balances in order
+	balances := make([]int, runs)
+	sorted := make([]int, runs)
+
+	for run := range balances {
+		balances[run] = startBalance
+		sorted[run] = startBalance
+	}
+
+	wentBelowZero := make([]bool, runs)
+	runsBelowZero := 0
+
+	if startBalance < 0 {
+		for run := range wentBelowZero {
+			wentBelowZero[run] = true
+		}
+
+		runsBelowZero = runs
+	}
+
+	result := SimulationResult{
+		Runs:        runs,
+		Percentiles: percentiles,
+		Days:        make([]SimulationDay, daysLen),
+	}
+
+	for day := range results {
+		if day%progress.interval == 0 {
+			progress.report(PhaseCalculating, day, daysLen)
+
+			if err := ctx.Err(); err != nil {
+				return SimulationResult{}, err
+			}
+		}
+
+		// the balances only need sorting again on days that they change
+		if len(dayOccurrences[day]) > 0 {
+			for run := range balances {
+				for _, o := range dayOccurrences[day] {
+					amt := o.amount
+					if o.dist != nil {
+						amt = o.dist.Sample(r, amt)
+					}
+
+					balances[run] += amt
+				}
+
+				if balances[run] < 0 && !wentBelowZero[run] {
+					wentBelowZero[run] = true
+					runsBelowZero++
+				}
+			}
+
+			copy(sorted, balances)
+			sort.Ints(sorted)
+		}
+
+		below := sort.SearchInts(sorted, 0)
+		bands := make([]int, len(percentiles))
+
+		for i, p := range percentiles {
+			bands[i] = sorted[int(math.Round(p/100*float64(runs-1)))]
+		}
+
+		result.Days[day] = SimulationDay{
+			Date:                 results[day].Date,
+			Balances:             bands,
+			ProbabilityBelowZero: float64(below) / float64(runs),
+		}
+	}
+
+	result.ProbabilityBelowZero = float64(runsBelowZero) / float64(runs)
+
+	progress.report(PhaseDone, daysLen, daysLen)
+
+	return result, nil
+}
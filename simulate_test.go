@@ -0,0 +1,216 @@
+package fplib_test
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+func TestDistributionSample(t *testing.T) {
+	t.Parallel()
+
+	r := rand.New(rand.NewSource(1)) //nolint:gosec
+
+	tests := []struct {
+		dist     fpl.Distribution
+		amount   int
+		min, max int
+	}{
+		{fpl.Distribution{Type: fpl.UNIFORM, Min: -5000, Max: -3000}, -4000, -5000, -3000},
+		// min and max are swapped
+		{fpl.Distribution{Type: fpl.UNIFORM, Min: -3000, Max: -5000}, -4000, -5000, -3000},
+		{fpl.Distribution{Type: fpl.EMPIRICAL, Samples: []int{-100, -200}}, -4000, -200, -100},
+		{fpl.Distribution{Type: fpl.EMPIRICAL}, -4000, -4000, -4000},
+		{fpl.Distribution{Type: fpl.NORMAL, StdDev: 0}, -4000, -4000, -4000},
+		{fpl.Distribution{Type: "nonsense"}, -4000, -4000, -4000},
+	}
+
+	for i, test := range tests {
+		for j := 0; j < 100; j++ {
+			got := test.dist.Sample(r, test.amount)
+			if got < test.min || got > test.max {
+				t.Logf("test %v failed: got %v but wanted between %v and %v", i, got, test.min, test.max)
+				t.FailNow()
+			}
+		}
+	}
+}
+
+//nolint:cyclop
+func TestSimulate(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	fixed := fpl.TX{
+		Amount:      -10000,
+		Name:        "Rent",
+		Active:      true,
+		Frequency:   fpl.MONTHLY,
+		Interval:    1,
+		StartsDay:   1,
+		StartsMonth: 1,
+		StartsYear:  2025,
+	}
+
+	variable := fpl.TX{
+		Amount:       -5000,
+		Name:         "Groceries",
+		Active:       true,
+		Frequency:    fpl.MONTHLY,
+		Interval:     1,
+		StartsDay:    15,
+		StartsMonth:  1,
+		StartsYear:   2025,
+		Distribution: &fpl.Distribution{Type: fpl.UNIFORM, Min: -7000, Max: -3000},
+	}
+
+	// without any distributions, every band matches GetResults
+	{
+		want, err := fpl.GetResults([]fpl.TX{fixed}, start, end, 200000, nil)
+		if err != nil {
+			t.Logf("GetResults failed: %v", err.Error())
+			t.FailNow()
+		}
+
+		got, err := fpl.Simulate([]fpl.TX{fixed}, start, end, 200000, 10, 1, nil, nil)
+		if err != nil {
+			t.Logf("Simulate failed: %v", err.Error())
+			t.FailNow()
+		}
+
+		if len(got.Days) != len(want) {
+			t.Logf("wrong day count: got %v, want %v", len(got.Days), len(want))
+			t.FailNow()
+		}
+
+		for i := range want {
+			for j, b := range got.Days[i].Balances {
+				if b != want[i].Balance {
+					t.Logf("day %v band %v: got %v, want %v", i, j, b, want[i].Balance)
+					t.FailNow()
+				}
+			}
+		}
+
+		if got.ProbabilityBelowZero != 0 {
+			t.Logf("wrong probability below zero: got %v, want 0", got.ProbabilityBelowZero)
+			t.FailNow()
+		}
+	}
+
+	// bands are ordered, bounded, and reproducible with the same seed
+	{
+		txs := []fpl.TX{fixed, variable}
+
+		got, err := fpl.Simulate(txs, start, end, 180000, 200, 42, nil, nil)
+		if err != nil {
+			t.Logf("Simulate failed: %v", err.Error())
+			t.FailNow()
+		}
+
+		again, _ := fpl.Simulate(txs, start, end, 180000, 200, 42, nil, nil)
+
+		last := got.Days[len(got.Days)-1]
+		for i := 1; i < len(last.Balances); i++ {
+			if last.Balances[i] < last.Balances[i-1] {
+				t.Logf("bands out of order: %v", last.Balances)
+				t.FailNow()
+			}
+		}
+
+		lo, hi := 180000-12*10000-12*7000, 180000-12*10000-12*3000
+		if last.Balances[0] < lo || last.Balances[len(last.Balances)-1] > hi {
+			t.Logf("bands out of bounds: %v (want between %v and %v)", last.Balances, lo, hi)
+			t.FailNow()
+		}
+
+		for i := range got.Days {
+			for j := range got.Days[i].Balances {
+				if got.Days[i].Balances[j] != again.Days[i].Balances[j] {
+					t.Logf("day %v band %v is not reproducible", i, j)
+					t.FailNow()
+				}
+			}
+		}
+
+		// the balance ends near zero, so some runs dip below it
+		if got.ProbabilityBelowZero <= 0 || got.ProbabilityBelowZero >= 1 {
+			t.Logf("wrong probability below zero: got %v", got.ProbabilityBelowZero)
+			t.FailNow()
+		}
+	}
+
+	// starting below zero counts even on days without any occurrences
+	{
+		got, err := fpl.Simulate([]fpl.TX{}, start, end, -1, 10, 1, nil, nil)
+		if err != nil || got.ProbabilityBelowZero != 1 || got.Days[0].ProbabilityBelowZero != 1 {
+			t.Logf("wrong probability below zero for a negative start: %+v (%v)", got.ProbabilityBelowZero, err)
+			t.FailNow()
+		}
+	}
+
+	// invalid input
+	{
+		_, err := fpl.Simulate([]fpl.TX{fixed}, end, start, 0, 10, 1, nil, nil)
+		if err == nil {
+			t.Logf("expected error for start after end")
+			t.FailNow()
+		}
+
+		_, err = fpl.Simulate([]fpl.TX{fixed}, start, end, 0, 0, 1, nil, nil)
+		if err == nil {
+			t.Logf("expected error for zero runs")
+			t.FailNow()
+		}
+
+		_, err = fpl.Simulate([]fpl.TX{fixed}, start, end, 0, 10, 1, []float64{101}, nil)
+		if err == nil {
+			t.Logf("expected error for percentile out of range")
+			t.FailNow()
+		}
+
+		distributions := []fpl.Distribution{
+			{Type: "bogus"},
+			{Type: "normal", StdDev: 100},
+			{Type: fpl.NORMAL, StdDev: -1},
+			{Type: fpl.EMPIRICAL},
+		}
+
+		for i := range distributions {
+			invalid := variable
+			invalid.Distribution = &distributions[i]
+
+			_, err = fpl.Simulate([]fpl.TX{invalid}, start, end, 0, 10, 1, nil, nil)
+			if err == nil {
+				t.Logf("expected error for distribution %+v", distributions[i])
+				t.FailNow()
+			}
+		}
+
+		// inactive transactions aren't simulated, so they aren't checked
+		inactive := variable
+		inactive.Active = false
+		inactive.Distribution = &distributions[0]
+
+		_, err = fpl.Simulate([]fpl.TX{inactive}, start, end, 0, 10, 1, nil, nil)
+		if err != nil {
+			t.Logf("an inactive tx's distribution threw error: %v", err.Error())
+			t.FailNow()
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err = fpl.SimulateContext(ctx, []fpl.TX{fixed}, start, end, 0, 10, 1, nil, nil)
+		if !errors.Is(err, context.Canceled) {
+			t.Logf("expected context.Canceled, got %v", err)
+			t.FailNow()
+		}
+	}
+}
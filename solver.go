@@ -0,0 +1,119 @@
+package fplib
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnaffordable is returned by the affordability solvers when the balance
+// drops below the floor even without the new expense.
+var ErrUnaffordable = errors.New("the balance drops below the floor even without the new expense")
+
+// Affordability is the outcome of an affordability query.
+type Affordability struct {
+	// The largest expense, as a positive number of cents, that keeps the
+	// balance at or above the floor through the end date.
+	Amount int
+	// The day that limits how large the expense can be; with the expense,
+	// this is the first day that the balance comes closest to the floor.
+	BindingDate time.Time
+	// The candidate transaction with its amount set to the solved expense,
+	// ready to be added to the transactions.
+	TX TX
+}
+
+// SolveAffordability finds the largest expense that can recur on the schedule
+// of the candidate transaction while keeping the balance at or above floor
+// from startDate through endDate. The candidate's Amount and AmountSchedule
+// are ignored.
+//
+// Because each occurrence of the expense lowers every later balance by the
+// same amount, the answer is derived directly from a single projection of the
+// existing transactions rather than by repeatedly projecting guesses.
+func SolveAffordability(tx []TX, candidate TX, startDate time.Time, endDate time.Time, startBalance int, floor int, statusHook func(status string)) (Affordability, error) {
+	return SolveAffordabilityWithOptions(context.Background(), tx, candidate, startDate, endDate, floor, ResultsOptions{
+		StartBalance: startBalance,
+		Progress:     StatusHookReporter(statusHook),
+	})
+}
+
+// SolveAffordabilityWithOptions is the same as SolveAffordability, except that
+// the start balance and progress reporting are controlled by opts, and if ctx
+// is cancelled or its deadline passes before the projection is done,
+// ctx.Err() is returned.
+func SolveAffordabilityWithOptions(ctx context.Context, tx []TX, candidate TX, startDate time.Time, endDate time.Time, floor int, opts ResultsOptions) (Affordability, error) {
+	results, err := GetResultsWithOptions(ctx, tx, startDate, endDate, opts)
+	if err != nil {
+		return Affordability{}, err
+	}
+
+	candidate.Active = true
+	candidate.Amount = 0
+	candidate.AmountSchedule = nil
+
+	occurrences, err := getOccurrences(&candidate, startDate, endDate)
+	if err != nil {
+		return Affordability{}, err
+	}
+
+	// the number of occurrences of the candidate on each day
+	counts := make(map[int64]int, len(occurrences))
+	for _, dt := range occurrences {
+		counts[dt.Unix()]++
+	}
+
+	affordability := Affordability{Amount: -1}
+	// the cumulative number of occurrences of the candidate so far
+	k := 0
+
+	for _, r := range results {
+		k += counts[r.Date.Unix()]
+
+		headroom := r.Balance - floor
+		if headroom < 0 {
+			return Affordability{}, fmt.Errorf("%w: %v on %v", ErrUnaffordable, FormatAsCurrency(r.Balance), GetNowDateString(r.Date))
+		}
+
+		if k == 0 {
+			continue
+		}
+
+		if limit := headroom / k; affordability.Amount < 0 || limit < affordability.Amount {
+			affordability.Amount = limit
+			affordability.BindingDate = r.Date
+		}
+	}
+
+	if affordability.Amount < 0 {
+		return Affordability{}, fmt.Errorf("tx %v never occurs between %v and %v", candidate.Name, GetNowDateString(startDate), GetNowDateString(endDate))
+	}
+
+	candidate.Amount = -affordability.Amount
+	affordability.TX = candidate
+
+	return affordability, nil
+}
+
+// SolveMaxPurchase finds the largest one-time purchase on the provided date
+// that keeps the balance at or above floor through endDate. See
+// SolveAffordability.
+func SolveMaxPurchase(tx []TX, on time.Time, startDate time.Time, endDate time.Time, startBalance int, floor int, statusHook func(status string)) (Affordability, error) {
+	candidate := newOneTimeTX("Purchase", 0, on)
+	candidate.ID = GetNewTX(on).ID
+
+	return SolveAffordability(tx, candidate, startDate, endDate, startBalance, floor, statusHook)
+}
+
+// SolveMaxMonthlyExpense finds the largest new monthly expense, starting on
+// the provided date and continuing through endDate, that keeps the balance at
+// or above floor. See SolveAffordability.
+func SolveMaxMonthlyExpense(tx []TX, from time.Time, startDate time.Time, endDate time.Time, startBalance int, floor int, statusHook func(status string)) (Affordability, error) {
+	candidate := GetNewTX(from)
+	candidate.EndsDay = 0
+	candidate.EndsMonth = 0
+	candidate.EndsYear = 0
+
+	return SolveAffordability(tx, candidate, startDate, endDate, startBalance, floor, statusHook)
+}
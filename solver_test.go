@@ -0,0 +1,97 @@
+package fplib_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestSolveAffordability(t *testing.T) {
+	t.Parallel()
+
+	// the balance alternates between $700 and $1000 every month
+	txs := []fpl.TX{monthlyTX("", "Rent", -30000, 1), monthlyTX("", "Paycheck", 30000, 15)}
+	start, end := date(2025, 1, 1), date(2025, 6, 30)
+
+	tests := []struct {
+		solve           func() (fpl.Affordability, error)
+		wantAmount      int
+		wantBindingDate time.Time
+		err             bool
+	}{
+		{
+			func() (fpl.Affordability, error) {
+				return fpl.SolveMaxPurchase(txs, date(2025, 3, 5), start, end, 100000, 0, nil)
+			},
+			70000, date(2025, 3, 5), false,
+		},
+		{
+			func() (fpl.Affordability, error) {
+				return fpl.SolveMaxPurchase(txs, date(2025, 3, 5), start, end, 100000, 20000, nil)
+			},
+			50000, date(2025, 3, 5), false,
+		},
+		{
+			// each occurrence lowers every later balance, so the last
+			// low point before the window ends is the binding one
+			func() (fpl.Affordability, error) {
+				return fpl.SolveMaxMonthlyExpense(txs, date(2025, 1, 20), start, end, 100000, 0, nil)
+			},
+			14000, date(2025, 6, 1), false,
+		},
+		{
+			// the balance is already below the floor
+			func() (fpl.Affordability, error) {
+				return fpl.SolveMaxPurchase(txs, date(2025, 3, 5), start, end, 100000, 80000, nil)
+			},
+			0, time.Time{}, true,
+		},
+		{
+			// the purchase is outside of the window
+			func() (fpl.Affordability, error) {
+				return fpl.SolveMaxPurchase(txs, date(2026, 3, 5), start, end, 100000, 0, nil)
+			},
+			0, time.Time{}, true,
+		},
+	}
+
+	for i, test := range tests {
+		got, err := test.solve()
+		if err != nil && !test.err {
+			t.Logf("test %v threw error when it wasn't supposed to: %v", i, err.Error())
+			t.FailNow()
+		} else if err != nil && test.err {
+			continue
+		} else if test.err {
+			t.Logf("test %v did not throw an error when it was supposed to", i)
+			t.FailNow()
+		}
+
+		if got.Amount != test.wantAmount || !got.BindingDate.Equal(test.wantBindingDate) {
+			t.Logf("test %v failed: got %v on %v but wanted %v on %v", i, got.Amount, got.BindingDate, test.wantAmount, test.wantBindingDate)
+			t.FailNow()
+		}
+
+		// adding the solved transaction must keep the balance above zero
+		results, err := fpl.GetResults(append([]fpl.TX{got.TX}, txs...), start, end, 100000, nil)
+		if err != nil {
+			t.Logf("test %v GetResults failed: %v", i, err.Error())
+			t.FailNow()
+		}
+
+		stats := fpl.CalculateStats(results)
+		if stats.MinBalance < 0 {
+			t.Logf("test %v failed: solved expense drops the balance to %v", i, stats.MinBalance)
+			t.FailNow()
+		}
+	}
+
+	_, err := fpl.SolveMaxPurchase(txs, date(2025, 3, 5), start, end, 100000, 80000, nil)
+	if !errors.Is(err, fpl.ErrUnaffordable) {
+		t.Logf("expected ErrUnaffordable, got %v", err)
+		t.FailNow()
+	}
+}
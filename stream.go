@@ -0,0 +1,198 @@
+package fplib
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/teambition/rrule-go"
+)
+
+// StreamResults produces the same results as GetResults, but instead of
+// building every result up front, it calls yield with each result in date
+// order as soon as it's calculated. Memory use is bounded by the number of
+// transactions rather than by the length of the window, which makes it
+// suitable for very long projections. If yield returns false, streaming stops
+// early without an error.
+func StreamResults(tx []TX, startDate time.Time, endDate time.Time, startBalance int, statusHook func(status string), yield func(r Result) bool) error {
+	return StreamResultsWithOptions(context.Background(), tx, startDate, endDate, ResultsOptions{
+		StartBalance: startBalance,
+		Progress:     StatusHookReporter(statusHook),
+	}, yield)
+}
+
+// StreamResultsWithOptions is the same as StreamResults, except that the start
+// balance and progress reporting are controlled by opts. If ctx is cancelled or
+// its deadline passes before every result has been yielded, streaming stops and
+// ctx.Err() is returned.
+func StreamResultsWithOptions(ctx context.Context, tx []TX, startDate time.Time, endDate time.Time, opts ResultsOptions, yield func(r Result) bool) error {
+	if startDate.After(endDate) {
+		return fmt.Errorf("start date is after end date: %v vs %v", startDate, endDate)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	progress := newProgressTracker(opts.Progress, opts.ProgressInterval)
+	progress.report(PhasePreparing, 0, 0)
+
+	days, err := rrule.NewRRule(
+		rrule.ROption{
+			Freq:    rrule.DAILY,
+			Dtstart: startDate,
+			Until:   endDate,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to construct rrule for results date window: %v", err.Error())
+	}
+
+	active := 0
+
+	for i := range tx {
+		if tx[i].Active {
+			active++
+		}
+	}
+
+	progress.report(PhaseRecurrences, 0, active)
+
+	// every active transaction contributes its next occurrence to the queue
+	q := &occurrenceQueue{}
+	done := 0
+
+	for i := range tx {
+		if !tx[i].Active {
+			continue
+		}
+
+		s, err := getRecurrence(&tx[i], startDate, endDate)
+		if err != nil {
+			return err
+		}
+
+		o := &streamedOccurrence{tx: &tx[i], index: i, next: s.Iterator(), occurrence: -1}
+		if o.advance(startDate, endDate) {
+			heap.Push(q, o)
+		}
+
+		done++
+		progress.step(PhaseRecurrences, done, active)
+	}
+
+	totals := runningTotals{balance: opts.StartBalance}
+	next := days.Iterator()
+	daysLen := countResultDays(startDate, endDate)
+
+	for record := 0; ; record++ {
+		dt, ok := next()
+		if !ok {
+			break
+		}
+
+		if record%progress.interval == 0 {
+			progress.report(PhaseCalculating, record, daysLen)
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		r := Result{Record: record, Date: dt}
+
+		var entries []ResultEntry
+
+		// occurrences that aren't exactly on one of the days are skipped,
+		// just as they are in GetResults
+		for q.Len() > 0 && !(*q)[0].dt.After(dt) {
+			o := (*q)[0]
+
+			if o.dt.Equal(dt) {
+				entries = append(entries, newResultEntry(o.tx, dayOccurrence{
+					day:        record,
+					occurrence: o.occurrence,
+					amount:     o.tx.GetAmountOn(o.dt),
+				}))
+			}
+
+			if o.advance(startDate, endDate) {
+				heap.Fix(q, 0)
+			} else {
+				heap.Pop(q)
+			}
+		}
+
+		totals.addDay(&r, entries)
+
+		if !yield(r) {
+			return nil
+		}
+	}
+
+	progress.report(PhaseDone, daysLen, daysLen)
+
+	return nil
+}
+
+// streamedOccurrence is the upcoming occurrence of a single transaction while
+// streaming results.
+type streamedOccurrence struct {
+	tx *TX
+	// The position of the transaction in the provided slice, which keeps the
+	// order of same-day entries consistent with GetResults.
+	index int
+	next  func() (time.Time, bool)
+	dt    time.Time
+	// The index of this occurrence among every occurrence of the transaction
+	// within the window.
+	occurrence int
+}
+
+// advance moves to the transaction's next occurrence within the window,
+// returning false when there are none left.
+func (o *streamedOccurrence) advance(startDate time.Time, endDate time.Time) bool {
+	for {
+		dt, ok := o.next()
+		if !ok || dt.After(endDate) {
+			return false
+		}
+
+		if dt.Before(startDate) {
+			continue
+		}
+
+		o.dt = dt
+		o.occurrence++
+
+		return true
+	}
+}
+
+// occurrenceQueue is a min-heap of upcoming occurrences, ordered by date and
+// then by the position of their transaction.
+type occurrenceQueue []*streamedOccurrence
+
+func (q occurrenceQueue) Len() int { return len(q) }
+
+func (q occurrenceQueue) Less(i, j int) bool {
+	if q[i].dt.Equal(q[j].dt) {
+		return q[i].index < q[j].index
+	}
+
+	return q[i].dt.Before(q[j].dt)
+}
+
+func (q occurrenceQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *occurrenceQueue) Push(x any) { *q = append(*q, x.(*streamedOccurrence)) }
+
+func (q *occurrenceQueue) Pop() any {
+	old := *q
+	n := len(old)
+	o := old[n-1]
+	*q = old[:n-1]
+
+	return o
+}
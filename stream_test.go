@@ -0,0 +1,135 @@
+package fplib_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+	"github.com/teambition/rrule-go"
+)
+
+//nolint:cyclop
+func TestStreamResults(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, time.February, 2, 0, 0, 0, 0, time.UTC)
+
+	txs := []fpl.TX{
+		{
+			ID:          "rent",
+			Amount:      -100000,
+			Name:        "Rent",
+			Active:      true,
+			Frequency:   fpl.MONTHLY,
+			Interval:    1,
+			StartsDay:   1,
+			StartsMonth: 1,
+			StartsYear:  2024,
+			AmountSchedule: []fpl.ScheduledAmount{
+				{Effective: time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC), Amount: -110000},
+			},
+		},
+		{
+			// same day and name as the first, to check ordering
+			ID:          "rent2",
+			Amount:      -5000,
+			Name:        "Rent",
+			Active:      true,
+			Frequency:   fpl.MONTHLY,
+			Interval:    1,
+			StartsDay:   1,
+			StartsMonth: 1,
+			StartsYear:  2024,
+		},
+		{
+			ID:          "pay",
+			Amount:      150000,
+			Name:        "Paycheck",
+			Active:      true,
+			Frequency:   fpl.WEEKLY,
+			Interval:    2,
+			StartsDay:   5,
+			StartsMonth: 1,
+			StartsYear:  2024,
+			Weekdays:    map[int]bool{rrule.FR.Day(): true},
+		},
+		{
+			ID:     "yearly",
+			Amount: -30000,
+			Name:   "Insurance",
+			Active: true,
+			// starts before the window
+			RRule: "DTSTART:20200315T000000Z\nRRULE:FREQ=YEARLY;INTERVAL=1",
+		},
+		{
+			ID:     "inactive",
+			Amount: -1,
+			Name:   "Inactive",
+			Active: false,
+		},
+	}
+
+	want, err := fpl.GetResults(txs, start, end, 10000, nil)
+	if err != nil {
+		t.Logf("GetResults failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	got := []fpl.Result{}
+
+	err = fpl.StreamResults(txs, start, end, 10000, nil, func(r fpl.Result) bool {
+		got = append(got, r)
+
+		return true
+	})
+	if err != nil {
+		t.Logf("StreamResults failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	if len(got) != len(want) {
+		t.Logf("wrong day count: got %v, want %v", len(got), len(want))
+		t.FailNow()
+	}
+
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Logf("day %v mismatch:\ngot  %+v\nwant %+v", i, got[i], want[i])
+			t.FailNow()
+		}
+	}
+
+	// stopping early
+	{
+		count := 0
+
+		err := fpl.StreamResults(txs, start, end, 10000, nil, func(_ fpl.Result) bool {
+			count++
+
+			return count < 10
+		})
+		if err != nil || count != 10 {
+			t.Logf("stopping early failed: got %v results, err %v", count, err)
+			t.FailNow()
+		}
+	}
+
+	// invalid input
+	{
+		err := fpl.StreamResults(txs, end, start, 0, nil, func(_ fpl.Result) bool { return true })
+		if err == nil {
+			t.Logf("expected error for start after end")
+			t.FailNow()
+		}
+
+		bad := []fpl.TX{{Active: true, RRule: "nonsense"}}
+
+		err = fpl.StreamResults(bad, start, end, 0, nil, func(_ fpl.Result) bool { return true })
+		if err == nil {
+			t.Logf("expected error for an invalid rrule")
+			t.FailNow()
+		}
+	}
+}
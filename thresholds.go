@@ -0,0 +1,80 @@
+package fplib
+
+import "time"
+
+// ThresholdInterval is a stretch of consecutive days where the balance was
+// below a threshold.
+type ThresholdInterval struct {
+	Threshold int
+	// The first day that the balance was below the threshold.
+	Start time.Time
+	// The first day that the balance was at or above the threshold again.
+	// This is the zero time if Recovered is false.
+	End time.Time
+	// Whether the balance recovered before the end of the results.
+	Recovered bool
+	// The lowest balance within the interval, and the first day it occurred.
+	MinBalance     int
+	MinBalanceDate time.Time
+	// The names of the expenses on the Start day that caused the balance to
+	// cross below the threshold. This is empty if the interval starts on the
+	// first result, since the balance may have been below the threshold
+	// before it.
+	CausedBy []string
+}
+
+// FindThresholdCrossings scans the provided results for every interval where
+// the balance drops below each of the provided thresholds, such as "when do
+// we dip below $500?". Intervals are grouped by threshold in the order that
+// the thresholds were provided, and sorted by date within each threshold.
+func FindThresholdCrossings(results []Result, thresholds ...int) []ThresholdInterval {
+	intervals := []ThresholdInterval{}
+
+	for _, threshold := range thresholds {
+		var current *ThresholdInterval
+
+		for i, r := range results {
+			if r.Balance >= threshold {
+				if current != nil {
+					current.End = r.Date
+					current.Recovered = true
+					intervals = append(intervals, *current)
+					current = nil
+				}
+
+				continue
+			}
+
+			if current == nil {
+				current = &ThresholdInterval{
+					Threshold:      threshold,
+					Start:          r.Date,
+					MinBalance:     r.Balance,
+					MinBalanceDate: r.Date,
+					CausedBy:       []string{},
+				}
+
+				if i > 0 {
+					for _, e := range r.DayEntries {
+						if e.Amount < 0 {
+							current.CausedBy = append(current.CausedBy, e.Name)
+						}
+					}
+				}
+
+				continue
+			}
+
+			if r.Balance < current.MinBalance {
+				current.MinBalance = r.Balance
+				current.MinBalanceDate = r.Date
+			}
+		}
+
+		if current != nil {
+			intervals = append(intervals, *current)
+		}
+	}
+
+	return intervals
+}
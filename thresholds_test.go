@@ -0,0 +1,78 @@
+package fplib_test
+
+import (
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestFindThresholdCrossings(t *testing.T) {
+	t.Parallel()
+
+	entry := func(name string, amount int) fpl.ResultEntry {
+		return fpl.ResultEntry{Name: name, Amount: amount}
+	}
+
+	results := []fpl.Result{
+		{Date: date(2025, 1, 1), Balance: 1000},
+		{Date: date(2025, 1, 2), Balance: 400, DayEntries: []fpl.ResultEntry{entry("Rent", -700), entry("Refund", 100)}},
+		{Date: date(2025, 1, 3), Balance: -100, DayEntries: []fpl.ResultEntry{entry("Car", -500)}},
+		{Date: date(2025, 1, 4), Balance: 200, DayEntries: []fpl.ResultEntry{entry("Refund", 300)}},
+		{Date: date(2025, 1, 5), Balance: 600, DayEntries: []fpl.ResultEntry{entry("Paycheck", 400)}},
+		{Date: date(2025, 1, 6), Balance: 300, DayEntries: []fpl.ResultEntry{entry("Groceries", -200), entry("Gas", -100)}},
+	}
+
+	tests := []struct {
+		thresholds []int
+		want       []fpl.ThresholdInterval
+	}{
+		{
+			[]int{500, 0},
+			[]fpl.ThresholdInterval{
+				{500, date(2025, 1, 2), date(2025, 1, 5), true, -100, date(2025, 1, 3), []string{"Rent"}},
+				{500, date(2025, 1, 6), time.Time{}, false, 300, date(2025, 1, 6), []string{"Groceries", "Gas"}},
+				{0, date(2025, 1, 3), date(2025, 1, 4), true, -100, date(2025, 1, 3), []string{"Car"}},
+			},
+		},
+		{
+			[]int{-1000},
+			[]fpl.ThresholdInterval{},
+		},
+		// the balance may have already been below the threshold before the
+		// first result, so nothing is blamed for it
+		{
+			[]int{2000},
+			[]fpl.ThresholdInterval{
+				{2000, date(2025, 1, 1), time.Time{}, false, -100, date(2025, 1, 3), []string{}},
+			},
+		},
+		{
+			[]int{},
+			[]fpl.ThresholdInterval{},
+		},
+	}
+
+	for i, test := range tests {
+		got := fpl.FindThresholdCrossings(results, test.thresholds...)
+		if len(got) != len(test.want) {
+			t.Logf("test %v failed: got %v intervals but wanted %v", i, len(got), len(test.want))
+			t.FailNow()
+		}
+
+		for j, want := range test.want {
+			g := got[j]
+			if g.Threshold != want.Threshold ||
+				!g.Start.Equal(want.Start) ||
+				!g.End.Equal(want.End) ||
+				g.Recovered != want.Recovered ||
+				g.MinBalance != want.MinBalance ||
+				!g.MinBalanceDate.Equal(want.MinBalanceDate) ||
+				fpl.GetCSVString(g.CausedBy) != fpl.GetCSVString(want.CausedBy) {
+				t.Logf("test %v interval %v failed: got %+v but wanted %+v", i, j, g, want)
+				t.FailNow()
+			}
+		}
+	}
+}
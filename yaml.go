@@ -0,0 +1,236 @@
+package fplib
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/teambition/rrule-go"
+	"gopkg.in/yaml.v3"
+)
+
+// TXFile is the top-level document of a transaction file, as read by LoadTXs
+// and written by SaveTXs.
+type TXFile struct {
+	// The version of the file format, which is always TXFileVersion when
+	// written. Files from before the format was versioned, which are just a
+	// list of transactions, are loaded with a version of 0.
+	Version  int            `yaml:"version" json:"version"`
+	Settings TXFileSettings `yaml:"settings" json:"settings"`
+	TXs      []TX           `yaml:"transactions" json:"transactions"`
+}
+
+// TXFileSettings are the settings that are saved alongside the transactions
+// in a transaction file.
+type TXFileSettings struct {
+	StartBalance int `yaml:"startBalance" json:"startBalance"` // in cents; 500 = $5.00
+	// The window to calculate results for. Zero values are left for the
+	// consumer to decide, and aren't written to YAML.
+	StartDate time.Time `yaml:"startDate,omitempty" json:"startDate"`
+	EndDate   time.Time `yaml:"endDate,omitempty" json:"endDate"`
+}
+
+// FileError is a problem with a transaction file at a specific line.
+type FileError struct {
+	Line int
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("line %v: %v", e.Line, e.Err.Error())
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// LoadTXs reads a YAML transaction file. Unknown keys are rejected so that
+// typos don't go unnoticed, and every error includes the line that caused it.
+// Problems with individual transactions, such as an invalid RRule, are
+// returned as a *FileError.
+func LoadTXs(r io.Reader) (TXFile, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return TXFile{}, fmt.Errorf("failed to read transaction file: %v", err.Error())
+	}
+
+	var root yaml.Node
+
+	err = yaml.Unmarshal(b, &root)
+	if err != nil {
+		return TXFile{}, fmt.Errorf("failed to parse transaction file: %v", err.Error())
+	}
+
+	f := TXFile{TXs: []TX{}}
+
+	// an empty file has no document at all
+	if len(root.Content) == 0 {
+		return f, nil
+	}
+
+	doc := root.Content[0]
+
+	var txNodes *yaml.Node
+
+	switch doc.Kind {
+	case yaml.SequenceNode:
+		txNodes = doc
+
+		err = decodeStrict(b, &f.TXs)
+	case yaml.MappingNode:
+		err = checkVersion(doc)
+		if err != nil {
+			return TXFile{}, err
+		}
+
+		txNodes = getMappingValue(doc, "transactions")
+
+		err = decodeStrict(b, &f)
+	default:
+		return TXFile{}, &FileError{
+			Line: doc.Line,
+			Err:  errors.New("expected a mapping of version, settings and transactions"),
+		}
+	}
+
+	if err != nil {
+		return TXFile{}, fmt.Errorf("failed to load transaction file: %v", err.Error())
+	}
+
+	if f.TXs == nil {
+		f.TXs = []TX{}
+	}
+
+	if txNodes != nil {
+		err = validateTXs(f.TXs, txNodes)
+		if err != nil {
+			return TXFile{}, err
+		}
+	}
+
+	return f, nil
+}
+
+// SaveTXs writes a YAML transaction file that LoadTXs can read. The version is
+// always set to TXFileVersion. Keys are written in a stable order: struct
+// fields in the order they're declared, and map keys sorted, so saving the same
+// transactions twice produces identical output.
+func SaveTXs(w io.Writer, f TXFile) error {
+	f.Version = TXFileVersion
+
+	if f.TXs == nil {
+		f.TXs = []TX{}
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+
+	err := enc.Encode(f)
+	if err != nil {
+		return fmt.Errorf("failed to save transaction file: %v", err.Error())
+	}
+
+	return enc.Close()
+}
+
+// decodeStrict decodes b into v, rejecting keys that v doesn't have.
+func decodeStrict(b []byte, v any) error {
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+
+	return dec.Decode(v)
+}
+
+// checkVersion returns an error if the document's version is missing or isn't
+// supported.
+func checkVersion(doc *yaml.Node) error {
+	node := getMappingValue(doc, "version")
+	if node == nil {
+		return &FileError{Line: doc.Line, Err: errors.New("missing version")}
+	}
+
+	var version int
+
+	err := node.Decode(&version)
+	if err != nil || version < 1 || version > TXFileVersion {
+		return &FileError{
+			Line: node.Line,
+			Err:  fmt.Errorf("unsupported version %v, expected 1 to %v", node.Value, TXFileVersion),
+		}
+	}
+
+	return nil
+}
+
+// validateTXs checks the transactions that were decoded from the provided
+// sequence node for problems that decoding doesn't catch.
+func validateTXs(tx []TX, seq *yaml.Node) error {
+	ids := make(map[string]int)
+
+	for i := range tx {
+		if i >= len(seq.Content) {
+			break
+		}
+
+		node := seq.Content[i]
+		line := func(key string) int {
+			if v := getMappingValue(node, key); v != nil {
+				return v.Line
+			}
+
+			return node.Line
+		}
+
+		if tx[i].RRule != "" {
+			_, err := rrule.StrToRRuleSet(tx[i].RRule)
+			if err != nil {
+				return &FileError{
+					Line: line("rrule"),
+					Err:  fmt.Errorf("invalid rrule for tx %v: %v", tx[i].Name, err.Error()),
+				}
+			}
+		}
+
+		for weekday := range tx[i].Weekdays {
+			if weekday < 0 || weekday > 6 {
+				return &FileError{
+					Line: line("weekdays"),
+					Err:  fmt.Errorf("invalid weekday %v for tx %v, expected 0 (Monday) to 6 (Sunday)", weekday, tx[i].Name),
+				}
+			}
+		}
+
+		if tx[i].ID == "" {
+			continue
+		}
+
+		if first, ok := ids[tx[i].ID]; ok {
+			return &FileError{
+				Line: line("id"),
+				Err:  fmt.Errorf("duplicate id %v, first used on line %v", tx[i].ID, first),
+			}
+		}
+
+		ids[tx[i].ID] = line("id")
+	}
+
+	return nil
+}
+
+// getMappingValue returns the value node for key in a mapping node, or nil if
+// the key isn't present.
+func getMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
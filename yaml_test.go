@@ -0,0 +1,151 @@
+package fplib_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	fpl "github.com/charles-m-knox/finance-planner-lib"
+)
+
+//nolint:cyclop
+func TestSaveAndLoadTXs(t *testing.T) {
+	t.Parallel()
+
+	txs := getBenchmarkTXs(20)
+	txs[3].AmountSchedule = []fpl.ScheduledAmount{{Effective: time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC), Amount: -500}}
+	txs[4].Distribution = &fpl.Distribution{Type: fpl.UNIFORM, Min: -100, Max: 100}
+	txs[5].Tags = []string{"b", "a"}
+	txs[6].RRule = "DTSTART:20250103T000000Z\nRRULE:FREQ=DAILY;INTERVAL=3"
+
+	f := fpl.TXFile{
+		Settings: fpl.TXFileSettings{
+			StartBalance: 12345,
+			StartDate:    time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:      time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC),
+		},
+		TXs: txs,
+	}
+
+	var saved bytes.Buffer
+
+	err := fpl.SaveTXs(&saved, f)
+	if err != nil {
+		t.Logf("SaveTXs failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	// the version comes first, then the settings, then the transactions
+	out := saved.String()
+	if !strings.HasPrefix(out, "version: 1\nsettings:\n  startBalance: 12345\n") || !strings.Contains(out, "\ntransactions:\n  - amount: ") {
+		t.Logf("unexpected layout:\n%v", out[:200])
+		t.FailNow()
+	}
+
+	loaded, err := fpl.LoadTXs(strings.NewReader(out))
+	if err != nil {
+		t.Logf("LoadTXs failed: %v", err.Error())
+		t.FailNow()
+	}
+
+	if loaded.Version != fpl.TXFileVersion || loaded.Settings != f.Settings || len(loaded.TXs) != len(txs) {
+		t.Logf("loaded the wrong file: %+v", loaded.Settings)
+		t.FailNow()
+	}
+
+	for i := range txs {
+		got, want := loaded.TXs[i], txs[i]
+		if got.ID != want.ID || got.Amount != want.Amount || got.RRule != want.RRule || len(got.Weekdays) != len(want.Weekdays) ||
+			len(got.AmountSchedule) != len(want.AmountSchedule) || len(got.Tags) != len(want.Tags) || (got.Distribution == nil) != (want.Distribution == nil) {
+			t.Logf("tx %v differs after loading: got %+v but wanted %+v", i, got, want)
+			t.FailNow()
+		}
+	}
+
+	// saving the same transactions again produces identical output
+	var resaved bytes.Buffer
+
+	err = fpl.SaveTXs(&resaved, loaded)
+	if err != nil {
+		t.Logf("SaveTXs failed the second time: %v", err.Error())
+		t.FailNow()
+	}
+
+	if resaved.String() != out {
+		t.Logf("output changed after loading and saving again")
+		t.FailNow()
+	}
+
+	// unset dates are left out rather than saved as the zero time
+	var unset bytes.Buffer
+
+	err = fpl.SaveTXs(&unset, fpl.TXFile{Settings: fpl.TXFileSettings{StartBalance: 1}})
+	if err != nil || strings.Contains(unset.String(), "Date") {
+		t.Logf("unexpected output for unset dates (%v):\n%v", err, unset.String())
+		t.FailNow()
+	}
+
+	reloaded, err := fpl.LoadTXs(strings.NewReader(unset.String()))
+	if err != nil || !reloaded.Settings.StartDate.IsZero() || !reloaded.Settings.EndDate.IsZero() {
+		t.Logf("unset dates didn't survive loading: %+v (%v)", reloaded.Settings, err)
+		t.FailNow()
+	}
+}
+
+func TestLoadTXs(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		// the number of transactions to expect
+		count int
+		// the line of the error to expect, if any
+		line int
+		// text that the error must contain, if any
+		contains string
+	}{
+		{"", 0, 0, ""},
+		{"version: 1\n", 0, 0, ""},
+		{"version: 1\nsettings:\n  startBalance: 100\n  startDate: 2025-01-01\ntransactions:\n  - name: a\n    amount: 5\n", 1, 0, ""},
+		// files from before the format was versioned
+		{"- name: a\n  amount: 5\n- name: b\n", 2, 0, ""},
+		{"settings:\n  startBalance: 100\n", 0, 1, "missing version"},
+		{"version: 2\n", 0, 1, "unsupported version"},
+		{"transactions: []\nversion: nope\n", 0, 2, "unsupported version"},
+		{"just a string", 0, 1, "expected a mapping"},
+		{"version: 1\ntransactions:\n  - name: a\n    amout: 5\n", 0, 0, "line 4"},
+		{"version: 1\ntransactions:\n  - name: a\n    amount: five\n", 0, 0, "line 4"},
+		{"version: 1\ntransactions: [\n", 0, 0, "line 2"},
+		{"version: 1\ntransactions:\n  - name: a\n  - name: b\n    rrule: nonsense\n", 0, 5, "invalid rrule for tx b"},
+		{"version: 1\ntransactions:\n  - name: a\n    weekdays:\n      7: true\n", 0, 5, "invalid weekday 7"},
+		{"version: 1\ntransactions:\n  - id: x\n  - id: y\n  - id: x\n", 0, 5, "first used on line 3"},
+	}
+
+	for i, test := range tests {
+		got, err := fpl.LoadTXs(strings.NewReader(test.input))
+
+		if test.line == 0 && test.contains == "" {
+			if err != nil || len(got.TXs) != test.count {
+				t.Logf("test %v: got %v transactions (%v), wanted %v", i, len(got.TXs), err, test.count)
+				t.Fail()
+			}
+
+			continue
+		}
+
+		if err == nil || !strings.Contains(err.Error(), test.contains) {
+			t.Logf("test %v: got error %v, wanted it to contain %q", i, err, test.contains)
+			t.Fail()
+
+			continue
+		}
+
+		var fileErr *fpl.FileError
+		if test.line != 0 && (!errors.As(err, &fileErr) || fileErr.Line != test.line) {
+			t.Logf("test %v: got error %v, wanted it on line %v", i, err, test.line)
+			t.Fail()
+		}
+	}
+}